@@ -0,0 +1,64 @@
+// amend.go
+//
+// Implements 'teryx amend', a wrapper around 'fossil amend' for fixing up the
+// comment, author, branch or visibility of an existing check-in.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// amendCmd handles the 'teryx amend' command.
+var amendCmd = &cobra.Command{
+	Use:   "amend <checkin>",
+	Short: "Amends the comment, author, branch or visibility of a check-in.",
+	Long: `Wraps 'fossil amend' to change the metadata of an existing check-in in the
+open checkout. At least one of --comment, --author, --branch or --hide must be given.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		checkin := args[0]
+		comment, _ := cmd.Flags().GetString("comment")
+		author, _ := cmd.Flags().GetString("author")
+		branch, _ := cmd.Flags().GetString("branch")
+		hide, _ := cmd.Flags().GetBool("hide")
+
+		fossilArgs := []string{"amend", checkin}
+		if comment != "" {
+			fossilArgs = append(fossilArgs, "--comment", comment)
+		}
+		if author != "" {
+			fossilArgs = append(fossilArgs, "--author", author)
+		}
+		if branch != "" {
+			fossilArgs = append(fossilArgs, "--branch", branch)
+		}
+		if hide {
+			fossilArgs = append(fossilArgs, "--hide")
+		}
+
+		if len(fossilArgs) == 2 {
+			log.Fatal("❌ Nothing to amend. Specify at least one of --comment, --author, --branch or --hide.")
+		}
+
+		fmt.Printf("🚀 Amending check-in '%s'...\n", checkin)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to amend check-in: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Check-in '%s' amended.\n", checkin)
+	},
+}
+
+func init() {
+	amendCmd.Flags().StringP("comment", "m", "", "Replace the check-in comment")
+	amendCmd.Flags().String("author", "", "Change the user recorded as the check-in author")
+	amendCmd.Flags().String("branch", "", "Move the check-in onto a new branch with this name")
+	amendCmd.Flags().Bool("hide", false, "Hide the check-in's branch from the timeline")
+
+	rootCmd.AddCommand(amendCmd)
+}
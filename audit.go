@@ -0,0 +1,163 @@
+// audit.go
+//
+// Implements 'teryx audit', which reports common security problems of a
+// repository in one list.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// auditFinding is one potential problem reported by 'teryx audit'.
+type auditFinding struct {
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	Message  string `json:"message"`
+}
+
+// userCapabilities returns the capability letters of a user, from
+// 'fossil user capabilities <login>'.
+func userCapabilities(repoArgs []string, login string) (string, error) {
+	output, err := captureCommand("", "fossil", append([]string{"user", "capabilities", login}, repoArgs...)...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// auditFilePermissions reports a repository file that other local users can
+// read, which exposes its password hashes, or write.
+func auditFilePermissions(repoPath string) ([]auditFinding, error) {
+	info, err := os.Stat(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	mode := info.Mode().Perm()
+	switch {
+	case mode&0002 != 0:
+		return []auditFinding{{"high", "file-permissions", fmt.Sprintf("%s is world-writable (mode %04o)", repoPath, mode)}}, nil
+	case mode&0004 != 0:
+		return []auditFinding{{"medium", "file-permissions", fmt.Sprintf("%s is world-readable (mode %04o); it contains password hashes", repoPath, mode)}}, nil
+	}
+	return nil, nil
+}
+
+// auditUsers reports users with setup or admin rights, and capabilities given
+// to everyone through the special "nobody" and "anonymous" users.
+func auditUsers(repoArgs []string) ([]auditFinding, error) {
+	users, err := listRepoUsers(repoArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []auditFinding
+	for _, login := range users {
+		caps, err := userCapabilities(repoArgs, login)
+		if err != nil {
+			return nil, err
+		}
+		switch login {
+		case "nobody", "anonymous":
+			who := "anyone"
+			if login == "anonymous" {
+				who = "anyone who logs in as anonymous"
+			}
+			switch {
+			case strings.ContainsAny(caps, "saik"):
+				findings = append(findings, auditFinding{"high", "anonymous-access", fmt.Sprintf("%s can write or administer the repository ('%s' has capabilities '%s')", who, login, caps)})
+			case strings.ContainsAny(caps, "oj"):
+				findings = append(findings, auditFinding{"low", "anonymous-access", fmt.Sprintf("%s can read the repository ('%s' has capabilities '%s'); fine for a public project", who, login, caps)})
+			}
+		default:
+			if strings.ContainsAny(caps, "sa") {
+				findings = append(findings, auditFinding{"info", "broad-capabilities", fmt.Sprintf("user '%s' has setup or admin rights (capabilities '%s')", login, caps)})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// auditRemote reports a remote-url that syncs over plain HTTP, sending
+// passwords and content unencrypted.
+func auditRemote(repoArgs []string) ([]auditFinding, error) {
+	output, err := captureCommand("", "fossil", append([]string{"remote"}, repoArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	remote := strings.TrimSpace(string(output))
+	if strings.HasPrefix(remote, "http://") {
+		return []auditFinding{{"medium", "tls", fmt.Sprintf("the remote %s is served without TLS; credentials and content travel unencrypted", remote)}}, nil
+	}
+	return nil, nil
+}
+
+// auditCmd handles the 'teryx audit' command.
+var auditCmd = &cobra.Command{
+	Use:   "audit [repository-file]",
+	Short: "Reports potential security problems of a repository.",
+	Long: `Checks the repository of the open checkout, or the repository file given as an
+argument or via -R, for common security problems and lists them by severity:
+
+  - the repository file is readable or writable by other local users
+  - users with setup (s) or admin (a) capabilities
+  - capabilities granted to everyone via the nobody and anonymous users
+  - a remote-url that syncs over plain http://`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		repoArgs := repositoryArgs(cmd, args)
+
+		repoPath := ""
+		if repoArgs != nil {
+			repoPath = repoArgs[1]
+		} else {
+			var err error
+			if repoPath, err = checkoutRepository(""); err != nil {
+				log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+			}
+		}
+
+		findings := []auditFinding{}
+		checks := []func() ([]auditFinding, error){
+			func() ([]auditFinding, error) { return auditFilePermissions(repoPath) },
+			func() ([]auditFinding, error) { return auditUsers(repoArgs) },
+			func() ([]auditFinding, error) { return auditRemote(repoArgs) },
+		}
+		for _, check := range checks {
+			result, err := check()
+			if err != nil {
+				log.Fatalf("❌ Audit failed: %v", err)
+			}
+			findings = append(findings, result...)
+		}
+
+		if asJSON {
+			if err := printJSON(findings); err != nil {
+				log.Fatalf("❌ Failed to encode findings: %v", err)
+			}
+			return
+		}
+
+		if len(findings) == 0 {
+			fmt.Println("✅ No problems found.")
+			return
+		}
+		for _, finding := range findings {
+			fmt.Printf("[%-6s] %s: %s\n", strings.ToUpper(finding.Severity), finding.Check, finding.Message)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(auditCmd)
+	auditCmd.Flags().Bool("json", false, "Print the findings as a JSON array")
+
+	rootCmd.AddCommand(auditCmd)
+}
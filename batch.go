@@ -0,0 +1,173 @@
+// batch.go
+//
+// Implements 'teryx batch', which runs a file of teryx command lines in order,
+// e.g. to provision a set of repositories reproducibly.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// batchStep is one command line of a batch file.
+type batchStep struct {
+	Line int
+	Args []string
+}
+
+// splitCommandLine splits a line into words like a POSIX shell would, without
+// any expansion: words are separated by whitespace, single quotes preserve
+// everything literally, and in double quotes or unquoted text a backslash
+// escapes the next character.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// parseBatchFile reads the steps of a batch file. Blank lines and lines
+// starting with '#' are skipped, and a leading "teryx" on a line is optional.
+// Every step must name a teryx command, so mistakes are caught before anything
+// runs.
+func parseBatchFile(content string) ([]batchStep, error) {
+	var steps []batchStep
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		args, err := splitCommandLine(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if args[0] == "teryx" {
+			args = args[1:]
+		}
+		if len(args) == 0 {
+			continue
+		}
+		found, _, err := rootCmd.Find(args)
+		if err != nil || found == rootCmd {
+			return nil, fmt.Errorf("line %d: unknown teryx command '%s'", i+1, args[0])
+		}
+		if found.Name() == "batch" {
+			return nil, fmt.Errorf("line %d: batch files can't run 'teryx batch'", i+1)
+		}
+		steps = append(steps, batchStep{Line: i + 1, Args: args})
+	}
+	return steps, nil
+}
+
+// batchCmd handles the 'teryx batch' command.
+var batchCmd = &cobra.Command{
+	Use:   "batch <file>",
+	Short: "Runs a file of teryx commands in order.",
+	Long: `Reads teryx command lines from a file, one per line, and runs them in order,
+stopping at the first one that fails unless --continue-on-error is given. Lines
+may start with "teryx", and blank lines and lines starting with '#' are
+skipped. Words are split as in a POSIX shell, with quotes and backslashes, but
+without variable expansion or globbing.
+
+Each line runs as a separate teryx process, with the global --quiet and
+--verbose flags passed on.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			log.Fatalf("❌ Cannot read batch file: %v", err)
+		}
+		steps, err := parseBatchFile(string(content))
+		if err != nil {
+			log.Fatalf("❌ %s: %v", args[0], err)
+		}
+		self, err := os.Executable()
+		if err != nil {
+			log.Fatalf("❌ Could not locate the teryx executable: %v", err)
+		}
+
+		globalArgs := globalFlagArgs()
+
+		var failed []int
+		for i, step := range steps {
+			fmt.Printf("▶️  [%d/%d] teryx %s\n", i+1, len(steps), strings.Join(step.Args, " "))
+			stepCmd := exec.Command(self, append(append([]string{}, globalArgs...), step.Args...)...)
+			stepCmd.Stdin = os.Stdin
+			stepCmd.Stdout = os.Stdout
+			stepCmd.Stderr = os.Stderr
+			if err := stepCmd.Run(); err != nil {
+				if !continueOnError {
+					log.Fatalf("❌ Line %d failed (%v); stopping. Use --continue-on-error to run the remaining lines.", step.Line, err)
+				}
+				fmt.Printf("⚠️ Line %d failed: %v\n", step.Line, err)
+				failed = append(failed, step.Line)
+			}
+		}
+
+		if len(failed) > 0 {
+			log.Fatalf("❌ %d of %d commands failed (lines %s).", len(failed), len(steps), strings.Trim(fmt.Sprint(failed), "[]"))
+		}
+		fmt.Printf("✅ Success! All %d commands completed.\n", len(steps))
+	},
+}
+
+func init() {
+	batchCmd.Flags().Bool("continue-on-error", false, "Keep running the remaining lines when one fails")
+
+	rootCmd.AddCommand(batchCmd)
+}
@@ -0,0 +1,96 @@
+// bisect.go
+//
+// Implements the 'teryx bisect' command group, a wrapper around 'fossil bisect'
+// for tracking down the check-in that introduced a regression.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// runBisect runs 'fossil bisect <subcommand> [checkin]' in the open checkout.
+func runBisect(subcommand string, args []string) {
+	fossilArgs := append([]string{"bisect", subcommand}, args...)
+	if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+		log.Fatalf("❌ 'bisect %s' failed: %v", subcommand, err)
+	}
+}
+
+// bisectCmd is the parent of the 'teryx bisect' subcommands.
+var bisectCmd = &cobra.Command{
+	Use:   "bisect",
+	Short: "Finds the check-in that introduced a bug by binary search.",
+	Long: `Wraps 'fossil bisect'. A typical session is:
+
+  teryx bisect start
+  teryx bisect bad             # the current check-in is broken
+  teryx bisect good <checkin>  # an older check-in that worked
+  ...test, then mark each check-in fossil moves you to as good or bad...
+  teryx bisect reset`,
+	PersistentPreRunE: checkoutPreRun,
+}
+
+// bisectStartCmd handles the 'teryx bisect start' command.
+var bisectStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Starts a new bisect session, discarding any previous one.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runBisect("reset", nil)
+		fmt.Println("✅ Bisect started. Mark a broken check-in with 'teryx bisect bad' and a working one with 'teryx bisect good'.")
+	},
+}
+
+// bisectGoodCmd handles the 'teryx bisect good' command.
+var bisectGoodCmd = &cobra.Command{
+	Use:   "good [checkin]",
+	Short: "Marks a check-in (the current one by default) as working.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runBisect("good", args)
+	},
+}
+
+// bisectBadCmd handles the 'teryx bisect bad' command.
+var bisectBadCmd = &cobra.Command{
+	Use:   "bad [checkin]",
+	Short: "Marks a check-in (the current one by default) as broken.",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runBisect("bad", args)
+	},
+}
+
+// bisectStatusCmd handles the 'teryx bisect status' command.
+var bisectStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows the check-ins still under consideration.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runBisect("status", nil)
+	},
+}
+
+// bisectResetCmd handles the 'teryx bisect reset' command.
+var bisectResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Ends the bisect session and forgets all good/bad marks.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runBisect("reset", nil)
+		fmt.Println("✅ Bisect reset.")
+	},
+}
+
+func init() {
+	bisectCmd.AddCommand(bisectStartCmd)
+	bisectCmd.AddCommand(bisectGoodCmd)
+	bisectCmd.AddCommand(bisectBadCmd)
+	bisectCmd.AddCommand(bisectStatusCmd)
+	bisectCmd.AddCommand(bisectResetCmd)
+	rootCmd.AddCommand(bisectCmd)
+}
@@ -0,0 +1,83 @@
+// changes.go
+//
+// Implements 'teryx changes', which lists the pending changes in the open
+// checkout, optionally as JSON for editor integrations and scripts.
+
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// fileChange is one entry of 'fossil changes' output.
+type fileChange struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// parseChanges parses 'fossil changes' output, whose lines have the form
+// "<STATUS>  <path>", into fileChange values with lower-case statuses.
+func parseChanges(output string) []fileChange {
+	var changes []fileChange
+	for _, line := range strings.Split(output, "\n") {
+		status, path, found := strings.Cut(strings.TrimSpace(line), " ")
+		if !found {
+			continue
+		}
+		changes = append(changes, fileChange{
+			Path:   strings.TrimSpace(path),
+			Status: strings.ToLower(status),
+		})
+	}
+	return changes
+}
+
+// pendingChanges returns the parsed pending changes of the checkout in dir.
+func pendingChanges(dir string) ([]fileChange, error) {
+	output, err := captureCommand(dir, "fossil", "changes")
+	if err != nil {
+		return nil, err
+	}
+	return parseChanges(string(output)), nil
+}
+
+// changesCmd handles the 'teryx changes' command.
+var changesCmd = &cobra.Command{
+	Use:   "changes",
+	Short: "Lists added, edited and deleted files in the open checkout.",
+	Long: `Wraps 'fossil changes'. With --json, prints an array of {"path", "status"}
+objects instead, where status is fossil's change type in lower case
+(e.g. "edited", "added", "deleted", "missing").`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if !asJSON {
+			if err := executeCommand("", "fossil", "changes"); err != nil {
+				log.Fatalf("❌ Failed to list changes: %v", err)
+			}
+			return
+		}
+
+		changes, err := pendingChanges("")
+		if err != nil {
+			log.Fatalf("❌ Failed to list changes: %v", err)
+		}
+		if changes == nil {
+			changes = []fileChange{} // encode as [] rather than null
+		}
+		if err := printJSON(changes); err != nil {
+			log.Fatalf("❌ Failed to encode changes: %v", err)
+		}
+	},
+}
+
+func init() {
+	changesCmd.Flags().Bool("json", false, "Print the changes as a JSON array")
+
+	rootCmd.AddCommand(changesCmd)
+}
@@ -0,0 +1,76 @@
+// changeset.go
+//
+// Implements 'teryx changeset' (alias 'teryx show'), which shows one
+// check-in's metadata together with its changes, like 'git show'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// changesetCmd handles the 'teryx changeset' command.
+var changesetCmd = &cobra.Command{
+	Use:     "changeset <check-in>",
+	Aliases: []string{"show"},
+	Short:   "Shows a check-in's details and its diff against its parent.",
+	Long: `Prints what 'fossil info' reports about the check-in (hash, parent, date,
+user, tags and comment), followed by the diff between its primary parent and
+the check-in. --stat summarizes the diff per file instead, and --files-only
+lists just the names of the changed files. Works in the open checkout or on
+the repository given with -R.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: rejectFlagConflicts(func(cmd *cobra.Command, args []string) error {
+		return checkoutOrRepositoryPreRun(cmd, nil)
+	}, [2]string{"stat", "files-only"}),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkin := args[0]
+		stat, _ := cmd.Flags().GetBool("stat")
+		filesOnly, _ := cmd.Flags().GetBool("files-only")
+		repoArgs := repositoryArgs(cmd, nil)
+
+		if err := executeCommand("", "fossil", append([]string{"info", checkin}, repoArgs...)...); err != nil {
+			log.Fatalf("❌ Failed to show check-in '%s': %v", checkin, err)
+		}
+		parent, err := fossilInfoField("", "parent", append([]string{checkin}, repoArgs...)...)
+		if err != nil {
+			fmt.Println("ℹ️  This is the repository's first check-in; it has no parent to compare with.")
+			return
+		}
+		parent, _, _ = strings.Cut(parent, " ")
+		fmt.Println()
+
+		diffArgs := append([]string{"diff", "--from", parent, "--to", checkin}, repoArgs...)
+		if !stat && !filesOnly {
+			if err := executeCommand("", "fossil", diffArgs...); err != nil {
+				log.Fatalf("❌ Failed to show diff: %v", err)
+			}
+			return
+		}
+
+		output, err := captureCommand("", "fossil", append(append([]string{}, diffArgs...), internalDiffArgs...)...)
+		if err != nil {
+			log.Fatalf("❌ Failed to compute diff: %v", err)
+		}
+		stats := parseDiffStat(string(output))
+		if stat {
+			printDiffStat(stats)
+			return
+		}
+		for _, fileStat := range stats {
+			fmt.Println(fileStat.Path)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(changesetCmd)
+	changesetCmd.Flags().Bool("stat", false, "Summarize the insertions and deletions per file instead of the full diff")
+	changesetCmd.Flags().Bool("files-only", false, "Only list the names of the changed files")
+
+	rootCmd.AddCommand(changesetCmd)
+}
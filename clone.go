@@ -0,0 +1,387 @@
+// clone.go
+//
+// Helpers used by 'teryx clone' for TLS client authentication, certificate
+// verification and the local directory layout.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// globalSetting returns the value of a global fossil setting and whether it is
+// set at all, parsed from 'fossil settings <name> --global', which prints
+// "<name> (global) <value>" when set and just "<name>" otherwise.
+func globalSetting(name string) (string, bool, error) {
+	return parseSetting(name, "(global)", "--global")
+}
+
+// repoSetting returns the value of a setting stored in a repository file, and
+// whether it is set there, from 'fossil settings <name> -R <repo>'.
+func repoSetting(repoPath, name string) (string, bool, error) {
+	return parseSetting(name, "(local)", "-R", repoPath)
+}
+
+// parseSetting runs 'fossil settings <name>' with extra arguments and returns
+// the value following the given scope marker, e.g. "(local)". It is a lookup,
+// so no banner is printed.
+func parseSetting(name, scope string, extraArgs ...string) (string, bool, error) {
+	output, err := lookupCommand("", "fossil", append([]string{"settings", name}, extraArgs...)...)
+	if err != nil {
+		return "", false, err
+	}
+	value, found := settingValue(string(output), scope)
+	return value, found, nil
+}
+
+// settingValue extracts the value following the scope marker from the output
+// of 'fossil settings <name>', and whether the setting is set in that scope.
+func settingValue(output, scope string) (string, bool) {
+	_, value, found := strings.Cut(output, scope)
+	if !found {
+		return "", false
+	}
+	return strings.TrimSpace(value), true
+}
+
+// withGlobalSetting temporarily sets a global fossil setting while fn runs,
+// then puts back the previous value (or unsets it if it wasn't set before).
+// It returns fn's error, or the error from changing the setting.
+func withGlobalSetting(name, value string, fn func() error) error {
+	previous, wasSet, err := globalSetting(name)
+	if err != nil {
+		return fmt.Errorf("could not read setting %s: %w", name, err)
+	}
+	if err := executeCommand("", "fossil", "settings", name, value, "--global"); err != nil {
+		return fmt.Errorf("could not set %s: %w", name, err)
+	}
+
+	fnErr := fn()
+
+	var restoreErr error
+	if wasSet {
+		restoreErr = executeCommand("", "fossil", "settings", name, previous, "--global")
+	} else {
+		restoreErr = executeCommand("", "fossil", "unset", name, "--global")
+	}
+	if restoreErr != nil {
+		fmt.Printf("⚠️ Could not restore global setting %s: %v\n", name, restoreErr)
+	}
+	return fnErr
+}
+
+// clientIdentityFile returns the path of a PEM file holding both the client
+// certificate and its private key, as fossil's --ssl-identity expects. When
+// certFile and keyFile are the same file, its absolute path is used;
+// otherwise the two are concatenated into a private temporary file. The
+// returned cleanup func removes any temporary file and must always be called.
+func clientIdentityFile(certFile, keyFile string) (string, func(), error) {
+	noop := func() {}
+	for _, file := range []string{certFile, keyFile} {
+		if info, err := os.Stat(file); err != nil {
+			return "", noop, fmt.Errorf("cannot read '%s': %w", file, err)
+		} else if info.IsDir() {
+			return "", noop, fmt.Errorf("'%s' is a directory", file)
+		}
+	}
+	if certFile == keyFile {
+		// fossil clone runs in the target directory, so hand it an absolute path.
+		identity, err := filepath.Abs(certFile)
+		return identity, noop, err
+	}
+
+	cert, err := os.ReadFile(certFile)
+	if err != nil {
+		return "", noop, err
+	}
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", noop, err
+	}
+
+	// os.CreateTemp creates the file with mode 0600, keeping the key private.
+	identity, err := os.CreateTemp("", "teryx-identity-*.pem")
+	if err != nil {
+		return "", noop, fmt.Errorf("could not create identity file: %w", err)
+	}
+	cleanup := func() { os.Remove(identity.Name()) }
+	defer identity.Close()
+
+	for _, pem := range [][]byte{cert, []byte("\n"), key} {
+		if _, err := identity.Write(pem); err != nil {
+			cleanup()
+			return "", noop, fmt.Errorf("could not write identity file: %w", err)
+		}
+	}
+	return identity.Name(), cleanup, nil
+}
+
+// runPostCloneHook runs hook, a shell command line given with
+// --post-clone-hook, in the new checkout, which is also exported to it as
+// TERYX_CHECKOUT_DIR. Its output is shown unless quiet is set, in which case
+// it is only included in the error if the hook fails.
+func runPostCloneHook(hook, checkoutDir string) error {
+	absCheckoutDir, err := filepath.Abs(checkoutDir)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("sh", "-c", hook)
+	cmd.Dir = absCheckoutDir
+	cmd.Env = append(os.Environ(), "TERYX_CHECKOUT_DIR="+absCheckoutDir)
+	printBanner(os.Stdout, cmd)
+	if quiet {
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w\n%s", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// normalizeRemoteURL cleans up a repository URL the way 'teryx clone' does,
+// dropping a trailing "/home" copied from the web UI, and checks that it
+// names a host.
+func normalizeRemoteURL(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(rawURL, "/home"))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s': %w", rawURL, err)
+	}
+	if parsedURL.Scheme == "" || parsedURL.Host == "" {
+		return "", fmt.Errorf("invalid URL '%s': expected e.g. https://host/path", rawURL)
+	}
+	return parsedURL.String(), nil
+}
+
+// repoOrigin returns the remote-url stored in the repository file at
+// repoPath, or "" if it has none or can't be read.
+func repoOrigin(repoPath string) string {
+	output, err := lookupCommand("", "fossil", "remote", "-R", repoPath)
+	if err != nil {
+		return ""
+	}
+	if remote := strings.TrimSpace(string(output)); remote != "off" {
+		return remote
+	}
+	return ""
+}
+
+// sameOrigin reports whether two repository URLs point at the same
+// repository: the same host and port and the same path, ignoring the scheme,
+// any user name or password, the case of the host and a trailing "/home" or
+// "/".
+func sameOrigin(a, b string) bool {
+	parsedA, errA := url.Parse(a)
+	parsedB, errB := url.Parse(b)
+	if errA != nil || errB != nil || parsedA.Host == "" {
+		return false
+	}
+	cleanPath := func(p string) string {
+		return strings.TrimSuffix(strings.TrimSuffix(strings.TrimRight(p, "/"), "/home"), "/")
+	}
+	return strings.EqualFold(parsedA.Hostname(), parsedB.Hostname()) &&
+		parsedA.Port() == parsedB.Port() &&
+		cleanPath(parsedA.Path) == cleanPath(parsedB.Path)
+}
+
+// clonePlan is where 'teryx clone' puts a repository and which URL it gives
+// fossil, as worked out by planClone and shown by 'teryx verify-url'.
+type clonePlan struct {
+	URL         string `json:"url"`
+	CleanURL    string `json:"clean_url"`
+	Username    string `json:"username"`
+	CloneURL    string `json:"clone_url"`
+	Layout      string `json:"layout"`
+	TargetDir   string `json:"target_dir"`
+	RepoFile    string `json:"repo_file"`
+	CheckoutDir string `json:"checkout_dir"`
+	scheme      string
+}
+
+// planClone works out what 'teryx clone' does with fossilURL: the trailing
+// "/home" of the web UI is dropped, the current user's name is added unless
+// anonymous or keepURL, and the local location follows the configured layout.
+// rename, if set, is a file name ending in .fossil that replaces the last part
+// of the URL path before the location is resolved, so the renamed file goes
+// through the same flat-layout collision check and --resume lookup as any
+// other. CloneURL is not redacted.
+func planClone(fossilURL, rename string, anonymous, keepURL, flat bool) (clonePlan, error) {
+	plan := clonePlan{URL: fossilURL, CleanURL: strings.TrimSuffix(fossilURL, "/home")}
+	parsedURL, err := url.Parse(plan.CleanURL)
+	if err != nil {
+		return plan, fmt.Errorf("invalid URL: %w", err)
+	}
+	plan.scheme = parsedURL.Scheme
+	urlPath := strings.TrimPrefix(parsedURL.Path, "/")
+	if parsedURL.Hostname() == "" || urlPath == "" {
+		return plan, fmt.Errorf("'%s' has no host or repository path; expected e.g. https://host/path", redactURL(fossilURL))
+	}
+
+	baseDir, err := fossilsBaseDir()
+	if err != nil {
+		return plan, err
+	}
+	config, err := loadConfig()
+	if err != nil {
+		return plan, err
+	}
+	flat = flat || config.Layout == "flat"
+	plan.Layout = "default"
+	if flat {
+		plan.Layout = "flat"
+	}
+
+	// With keepURL, the URL is passed to fossil exactly as given, so the
+	// stored remote-url matches it; the cleaned URL only shapes the layout.
+	switch {
+	case keepURL:
+		plan.CloneURL = fossilURL
+	case anonymous:
+		plan.CloneURL = parsedURL.String()
+	default:
+		currentUser, err := user.Current()
+		if err != nil {
+			return plan, fmt.Errorf("could not get current user: %w", err)
+		}
+		plan.Username = currentUser.Username
+		parsedURL.User = url.User(plan.Username)
+		plan.CloneURL = parsedURL.String()
+	}
+
+	namePath := urlPath
+	if rename != "" {
+		namePath = filepath.Join(filepath.Dir(urlPath), rename)
+	}
+	plan.TargetDir, plan.RepoFile = cloneLocation(baseDir, parsedURL.Hostname(), namePath, plan.CleanURL, flat)
+	plan.CheckoutDir = standardCheckoutDir(filepath.Join(plan.TargetDir, plan.RepoFile))
+	return plan, nil
+}
+
+// cloneLocation returns the directory and repository file name for a clone of
+// fossilURL, the repository at urlPath on hostname. The default layout mirrors
+// the server: <baseDir>/<hostname>/<dir of urlPath>/<name>.fossil. The flat
+// layout puts every clone directly in baseDir and appends the host to the name
+// when a repository from another origin already uses it. An existing file
+// cloned from fossilURL itself keeps the plain name, so re-cloning, --resume
+// and --workdir-only all find it instead of stepping around it.
+func cloneLocation(baseDir, hostname, urlPath, fossilURL string, flat bool) (string, string) {
+	repoBaseName := strings.TrimSuffix(filepath.Base(urlPath), ".fossil")
+	if !flat {
+		return filepath.Join(baseDir, hostname, filepath.Dir(urlPath)), repoBaseName + ".fossil"
+	}
+
+	plainName := repoBaseName + ".fossil"
+	plainPath := filepath.Join(baseDir, plainName)
+	if _, err := os.Stat(plainPath); err == nil && !sameOrigin(repoOrigin(plainPath), fossilURL) {
+		return baseDir, repoBaseName + "-" + hostname + ".fossil"
+	}
+	return baseDir, plainName
+}
+
+// cloneCompleteMarker names the config row 'teryx clone' writes into a
+// repository once it has been downloaded in full.
+const cloneCompleteMarker = "teryx-clone-complete"
+
+// markCloneComplete records in the repository file at repoPath that its clone
+// finished, for isPartialClone.
+func markCloneComplete(repoPath string) error {
+	_, err := captureCommand("", "fossil", "sql", "-R", repoPath,
+		"REPLACE INTO config(name, value, mtime) VALUES('"+cloneCompleteMarker+"', 1, strftime('%s', 'now'));")
+	return err
+}
+
+// isPartialClone reports whether the existing repository file at repoPath was
+// left behind by an interrupted 'teryx clone'. The decision rests on the file
+// itself: a finished clone carries cloneCompleteMarker, and fossil records a
+// ckout: row for every checkout opened from it, wherever that is, so clones
+// from before the marker existed count as complete too once opened. A file
+// fossil can't read at all is an error.
+func isPartialClone(repoPath string) (bool, error) {
+	query := "SELECT count(*) FROM config WHERE name = '" + cloneCompleteMarker + "' OR name GLOB 'ckout:*';"
+	output, err := captureCommand("", "fossil", "sql", "--readonly", "-R", repoPath, query)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not a readable repository; delete it and clone again: %w", repoPath, err)
+	}
+	return strings.TrimSpace(string(output)) == "0", nil
+}
+
+// validateOpenDir checks, before anything is cloned, that a --open-dir is an
+// existing directory that isn't already part of a checkout.
+func validateOpenDir(openDir string) error {
+	info, err := os.Stat(openDir)
+	if err != nil {
+		return fmt.Errorf("cannot use --open-dir: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("--open-dir '%s' is not a directory", openDir)
+	}
+	if requireCheckout(openDir) == nil {
+		return fmt.Errorf("--open-dir '%s' already contains, or is inside, a Fossil checkout", openDir)
+	}
+	return nil
+}
+
+// openCloneCheckout opens the checkout of a fresh clone and returns its
+// directory: by default a <name> directory next to the repository file in
+// repoDir, or, with --open-dir, the existing directory openDir.
+func openCloneCheckout(repoDir, fossilFileName, openDir string) (string, error) {
+	if openDir == "" {
+		return openRepoCheckout(repoDir, fossilFileName)
+	}
+
+	repoPath, err := filepath.Abs(filepath.Join(repoDir, fossilFileName))
+	if err != nil {
+		return "", err
+	}
+	if err := openCheckoutIn(openDir, repoPath, false); err != nil {
+		return "", err
+	}
+	return filepath.Abs(openDir)
+}
+
+// branchCheckoutDir returns the directory 'clone --branch' opens a checkout of
+// branch in: <repoDir>/<name>-<branch> for <repoDir>/<name>.fossil, beside the
+// main checkout. Slashes in the branch name become dashes.
+func branchCheckoutDir(repoDir, fossilFileName, branch string) string {
+	name := strings.TrimSuffix(fossilFileName, ".fossil") + "-" + strings.NewReplacer("/", "-", `\`, "-").Replace(branch)
+	return filepath.Join(repoDir, name)
+}
+
+// openBranchCheckouts opens an additional checkout of each of branches from
+// the repository file in repoDir, each in its own branchCheckoutDir, so
+// several branches can be worked on side by side. None are opened if any of
+// the directories already exists. It returns the directories it opened, also
+// when a later one fails.
+func openBranchCheckouts(repoDir, fossilFileName string, branches []string) ([]string, error) {
+	repoPath, err := filepath.Abs(filepath.Join(repoDir, fossilFileName))
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, branch := range branches {
+		dir, err := filepath.Abs(branchCheckoutDir(repoDir, fossilFileName, branch))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(dir); err == nil {
+			return nil, fmt.Errorf("'%s' already exists, so no branch checkouts were opened", dir)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	for i, branch := range branches {
+		// --workdir creates the directory and opens the checkout there.
+		if err := executeCommand(repoDir, "fossil", "open", repoPath, branch, "--workdir", dirs[i]); err != nil {
+			return dirs[:i], fmt.Errorf("failed to open a checkout of branch '%s': %w", branch, err)
+		}
+	}
+	return dirs, nil
+}
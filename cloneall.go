@@ -0,0 +1,123 @@
+// cloneall.go
+//
+// Implements 'teryx clone-all', which clones every repository listed under
+// repos: in the config file that isn't present locally yet.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cloneTargetPath returns where 'teryx clone' puts the repository file for
+// fossilURL, resolving the flat layout the same way a fresh clone does.
+func cloneTargetPath(baseDir, fossilURL string, flat bool) (string, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(fossilURL, "/home"))
+	if err != nil {
+		return "", err
+	}
+	urlPath := strings.TrimPrefix(parsedURL.Path, "/")
+	if parsedURL.Hostname() == "" || urlPath == "" {
+		return "", fmt.Errorf("'%s' has no host or repository path", fossilURL)
+	}
+	dir, name := cloneLocation(baseDir, parsedURL.Hostname(), urlPath, parsedURL.String(), flat)
+	return filepath.Join(dir, name), nil
+}
+
+// cloneAllCmd handles the 'teryx clone-all' command.
+var cloneAllCmd = &cobra.Command{
+	Use:   "clone-all",
+	Short: "Clones every repository listed in the config file that is missing locally.",
+	Long: `Reads the repos: list of the config file, e.g.
+
+  repos:
+  - https://fossil.example.com/project
+  - https://fossil.example.com/tools/build
+
+and runs 'teryx clone' for each URL whose repository file isn't in the clone
+layout yet. Repositories that are already present are skipped, and a failed
+clone doesn't stop the others. A summary of what was cloned, skipped and failed
+is printed at the end.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(config.Repos) == 0 {
+			path, _ := configPath()
+			log.Fatalf("❌ No repos: list in %s.", path)
+		}
+		baseDir, err := fossilsBaseDir()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		self, err := os.Executable()
+		if err != nil {
+			log.Fatalf("❌ Could not locate the teryx executable: %v", err)
+		}
+
+		var cloned, skipped, failed []string
+		for _, fossilURL := range config.Repos {
+			repoPath, err := cloneTargetPath(baseDir, fossilURL, config.Layout == "flat")
+			if err != nil {
+				fmt.Printf("⚠️ Skipping invalid URL: %v\n", err)
+				failed = append(failed, fossilURL)
+				continue
+			}
+			// A file at the target only counts as present if it was cloned
+			// from this URL; anything else is a collision 'teryx clone' can't
+			// step around.
+			if _, err := os.Stat(repoPath); err == nil {
+				if !sameOrigin(repoOrigin(repoPath), fossilURL) {
+					fmt.Printf("⚠️ %s is taken by a repository from another origin; not cloning %s\n", repoPath, redactURL(fossilURL))
+					failed = append(failed, fossilURL)
+					continue
+				}
+				fmt.Printf("⏭️  %s is already at %s\n", redactURL(fossilURL), repoPath)
+				skipped = append(skipped, fossilURL)
+				continue
+			}
+
+			clone := exec.Command(self, append(globalFlagArgs(), "clone", fossilURL)...)
+			clone.Stdin = os.Stdin
+			clone.Stdout = os.Stdout
+			clone.Stderr = os.Stderr
+			if err := clone.Run(); err != nil {
+				fmt.Printf("⚠️ Cloning %s failed: %v\n", redactURL(fossilURL), err)
+				failed = append(failed, fossilURL)
+				continue
+			}
+			cloned = append(cloned, fossilURL)
+		}
+
+		if !quiet {
+			fmt.Printf("ℹ️  %d cloned, %d skipped, %d failed.\n", len(cloned), len(skipped), len(failed))
+			for _, fossilURL := range cloned {
+				fmt.Printf("   cloned   %s\n", redactURL(fossilURL))
+			}
+			for _, fossilURL := range skipped {
+				fmt.Printf("   skipped  %s\n", redactURL(fossilURL))
+			}
+			for _, fossilURL := range failed {
+				fmt.Printf("   failed   %s\n", redactURL(fossilURL))
+			}
+		}
+		if len(failed) > 0 {
+			log.Fatalf("❌ %d of %d repositories could not be cloned.", len(failed), len(config.Repos))
+		}
+		fmt.Println("✅ Success! All listed repositories are present.")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloneAllCmd)
+}
@@ -0,0 +1,308 @@
+// commit.go
+//
+// Implements 'teryx commit', a wrapper around 'fossil commit' for the open
+// checkout.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// readCommitMessage returns the check-in comment in messageFile, or on stdin
+// when messageFile is "-", without surrounding whitespace. An empty comment is
+// an error, rather than falling back to the editor.
+func readCommitMessage(messageFile string) (string, error) {
+	var content []byte
+	var err error
+	if messageFile == "-" {
+		if content, err = io.ReadAll(os.Stdin); err != nil {
+			return "", fmt.Errorf("failed to read commit message from stdin: %w", err)
+		}
+	} else if content, err = os.ReadFile(messageFile); err != nil {
+		return "", fmt.Errorf("cannot read message file: %w", err)
+	}
+	message := strings.TrimSpace(string(content))
+	if message == "" {
+		return "", errors.New("the commit message is empty")
+	}
+	return message, nil
+}
+
+// autosyncPushes reports whether fossil will push after committing to the
+// given repository, i.e. whether its autosync setting, from the repository or
+// else the global settings, is on (fossil's default) rather than off or
+// pullonly.
+func autosyncPushes(repoPath string) (bool, error) {
+	value, ok, err := repoSetting(repoPath, "autosync")
+	if err == nil && !ok {
+		value, ok, err = globalSetting("autosync")
+	}
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, nil
+	}
+	switch strings.ToLower(value) {
+	case "off", "0", "false", "no", "pullonly":
+		return false, nil
+	}
+	return true, nil
+}
+
+// validateTagName checks a --tag value against fossil's rules for tag names,
+// so a bad name is caught before the commit rather than after it.
+func validateTagName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("tag names can't be empty")
+	case strings.HasPrefix(name, "-"):
+		return fmt.Errorf("tag '%s' can't start with '-'", name)
+	case strings.HasPrefix(name, "sym-"):
+		return fmt.Errorf("tag '%s' can't start with 'sym-', which fossil uses internally", name)
+	case strings.IndexFunc(name, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0:
+		return fmt.Errorf("tag '%s' can't contain whitespace", name)
+	}
+	return nil
+}
+
+// validateCommitFiles checks that each path given to a partial commit is
+// tracked and has pending changes, which fossil would otherwise either reject
+// after opening the editor or silently skip.
+func validateCommitFiles(paths []string) error {
+	for _, path := range paths {
+		output, err := captureCommand("", "fossil", "changes", path)
+		if err != nil {
+			return fmt.Errorf("could not check '%s': %w", path, err)
+		}
+		if len(parseChanges(string(output))) > 0 {
+			continue
+		}
+		tracked, err := captureCommand("", "fossil", "ls", path)
+		if err != nil || strings.TrimSpace(string(tracked)) == "" {
+			return fmt.Errorf("'%s' is not tracked; add it with 'fossil add' first", path)
+		}
+		return fmt.Errorf("'%s' has no changes to commit", path)
+	}
+	return nil
+}
+
+// checkinPushed reports whether the check-in with the given full hash has
+// already been sent to a remote: the repository has a remote-url and the
+// check-in is no longer in fossil's table of unsent artifacts.
+func checkinPushed(hash string) (bool, error) {
+	remote, err := captureCommand("", "fossil", "remote")
+	if err != nil {
+		return false, err
+	}
+	if value := strings.TrimSpace(string(remote)); value == "" || value == "off" {
+		return false, nil
+	}
+	if strings.Trim(hash, "0123456789abcdef") != "" {
+		return false, fmt.Errorf("unexpected check-in hash '%s'", hash)
+	}
+	query := "SELECT count(*) FROM unsent JOIN blob USING(rid) WHERE blob.uuid = '" + hash + "'"
+	output, err := captureCommand("", "fossil", "sql", "--readonly", query)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(output)) == "0", nil
+}
+
+// amendLastCheckin replaces the comment of the open checkout's current
+// check-in, normally the one just committed, with message, or with what the
+// user writes in their editor when message is empty. A check-in that was
+// already pushed is only amended with force, since others may have it.
+func amendLastCheckin(message string, force bool) {
+	entries, err := fetchTimeline("-n", "1", "current")
+	if err != nil || len(entries) == 0 {
+		log.Fatalf("❌ Could not find the current check-in: %v", err)
+	}
+	last := entries[0]
+
+	pushed, err := checkinPushed(last.Hash)
+	if err != nil {
+		log.Fatalf("❌ Could not tell whether check-in %.10s was synced: %v", last.Hash, err)
+	}
+	if pushed && !force {
+		log.Fatalf("❌ Check-in %.10s was already synced to the remote; pass --force to amend it anyway.", last.Hash)
+	}
+
+	if message == "" {
+		template := fmt.Sprintf("%s\n\n# Enter the new comment for check-in %.10s. Lines starting with '#' are ignored.\n", last.Comment, last.Hash)
+		if message, err = editText(template); err != nil {
+			log.Fatalf("❌ Failed to edit the comment: %v", err)
+		}
+		if message == "" {
+			log.Fatal("❌ Empty comment; check-in left unchanged.")
+		}
+	}
+	if message == last.Comment {
+		fmt.Println("ℹ️  The comment is unchanged; nothing to amend.")
+		return
+	}
+
+	fmt.Printf("🚀 Amending the comment of check-in %.10s...\n", last.Hash)
+	if err := executeCommand("", "fossil", "amend", last.Hash, "--comment", message); err != nil {
+		log.Fatalf("❌ Failed to amend check-in: %v", err)
+	}
+	if pushed {
+		fmt.Println("ℹ️  The amendment reaches the remote with the next sync.")
+	}
+	fmt.Printf("✅ Success! Check-in %.10s now reads: %s\n", last.Hash, message)
+}
+
+// commitCmd handles the 'teryx commit' command.
+var commitCmd = &cobra.Command{
+	Use:   "commit [file...]",
+	Short: "Commits the changes in the open checkout.",
+	Long: `Wraps 'fossil commit' for the open checkout. Without --message, fossil opens
+your editor for the check-in comment. --message-file (-F) reads the comment from
+a file, or from stdin when given '-'.
+
+Whether fossil syncs with the remote after committing depends on the
+repository's autosync setting. --no-sync skips the sync for this commit even
+when autosync is on; --sync syncs even when autosync is off or pullonly.
+
+--branch starts a new branch with the check-in, and --tag (repeatable) tags it
+in the same step, e.g. for a release.
+
+Files given as arguments, or with --only (repeatable), limit the check-in to
+those paths; other changes stay pending. Each must be tracked and changed.
+
+--amend-last commits nothing, but replaces the comment of the checkout's
+current check-in, normally the one just made, with -m, -F or what you write
+in your editor. A check-in that was already synced is only amended with
+--force.`,
+	Args: cobra.ArbitraryArgs,
+	PreRunE: rejectFlagConflicts(checkoutPreRun,
+		[2]string{"message", "message-file"},
+		[2]string{"sync", "no-sync"},
+		[2]string{"amend-last", "author"},
+		[2]string{"amend-last", "branch"},
+		[2]string{"amend-last", "tag"},
+		[2]string{"amend-last", "only"},
+		[2]string{"amend-last", "sync"},
+		[2]string{"amend-last", "no-sync"},
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		message, _ := cmd.Flags().GetString("message")
+		author, _ := cmd.Flags().GetString("author")
+		messageFile, _ := cmd.Flags().GetString("message-file")
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+		forceSync, _ := cmd.Flags().GetBool("sync")
+		branch, _ := cmd.Flags().GetString("branch")
+		tags, _ := cmd.Flags().GetStringArray("tag")
+		only, _ := cmd.Flags().GetStringArray("only")
+		files := append(append([]string{}, args...), only...)
+		amendLast, _ := cmd.Flags().GetBool("amend-last")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if force && !amendLast {
+			log.Fatal("❌ --force is only used together with --amend-last.")
+		}
+		if messageFile != "" {
+			var err error
+			if message, err = readCommitMessage(messageFile); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+		if amendLast {
+			if len(files) > 0 {
+				log.Fatal("❌ --amend-last changes a comment only and takes no files.")
+			}
+			amendLastCheckin(message, force)
+			return
+		}
+
+		if err := validateCommitFiles(files); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		fossilArgs := []string{"commit"}
+		if message != "" {
+			fossilArgs = append(fossilArgs, "-m", message)
+		}
+
+		if author != "" {
+			// Attribute the check-in to someone other than the default user. An
+			// unknown author is allowed, since fossil doesn't require the user
+			// to exist, but it's usually a typo worth pointing out.
+			users, err := listRepoUsers(nil)
+			if err != nil {
+				fmt.Printf("⚠️ Could not list repository users to validate --author: %v\n", err)
+			} else if !slices.Contains(users, author) {
+				fmt.Printf("⚠️ Author '%s' is not a user of this repository; committing anyway.\n", author)
+			}
+			fossilArgs = append(fossilArgs, "--user-override", author)
+		}
+
+		if branch != "" {
+			fossilArgs = append(fossilArgs, "--branch", branch)
+		}
+		for _, tag := range tags {
+			if err := validateTagName(tag); err != nil {
+				log.Fatalf("❌ Invalid --tag: %v", err)
+			}
+			fossilArgs = append(fossilArgs, "--tag", tag)
+		}
+		if noSync {
+			fossilArgs = append(fossilArgs, "--nosync")
+		}
+		fossilArgs = append(fossilArgs, files...)
+		// With --sync, an explicit sync is only needed when autosync won't push.
+		syncAfter := false
+		if forceSync {
+			repoPath, err := checkoutRepository("")
+			if err != nil {
+				log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+			}
+			pushes, err := autosyncPushes(repoPath)
+			if err != nil {
+				log.Fatalf("❌ Could not read the autosync setting: %v", err)
+			}
+			syncAfter = !pushes
+		}
+
+		if len(files) > 0 {
+			fmt.Printf("🚀 Committing changes to %d path(s)...\n", len(files))
+		} else {
+			fmt.Println("🚀 Committing changes...")
+		}
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to commit: %v", err)
+		}
+		if syncAfter {
+			if err := executeCommand("", "fossil", "sync"); err != nil {
+				log.Fatalf("❌ Changes were committed, but the sync failed: %v", err)
+			}
+		}
+
+		fmt.Println("✅ Success! Changes committed.")
+	},
+}
+
+func init() {
+	commitCmd.Flags().StringP("message", "m", "", "Check-in comment (opens your editor when omitted)")
+	commitCmd.Flags().String("author", "", "Record the check-in as made by this user instead of the default user")
+	commitCmd.Flags().StringP("message-file", "F", "", "Read the check-in comment from a file ('-' for stdin)")
+	commitCmd.Flags().StringP("branch", "b", "", "Commit onto a new branch with this name")
+	commitCmd.Flags().StringArray("tag", nil, "Tag the new check-in (repeatable)")
+	commitCmd.Flags().StringArray("only", nil, "Commit only this file (repeatable, same as a file argument)")
+	commitCmd.Flags().Bool("amend-last", false, "Replace the comment of the current check-in instead of committing")
+	commitCmd.Flags().Bool("force", false, "With --amend-last, amend even a check-in that was already synced")
+	commitCmd.Flags().Bool("no-sync", false, "Don't sync with the remote after committing, even if autosync is on")
+	commitCmd.Flags().Bool("sync", false, "Sync with the remote after committing, even if autosync is off")
+
+	rootCmd.AddCommand(commitCmd)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCommitMessage(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"one line", "Fix the build\n", "Fix the build", false},
+		{"surrounding whitespace is dropped", "\n  Fix the build\n\nDetails here.\n\n", "Fix the build\n\nDetails here.", false},
+		{"empty file", "", "", true},
+		{"only whitespace", " \n\t\n", "", true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readCommitMessage(path)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readCommitMessage() = %q, %v, want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+	if _, err := readCommitMessage(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("readCommitMessage() of a missing file succeeded")
+	}
+}
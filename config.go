@@ -0,0 +1,138 @@
+// config.go
+//
+// Loads teryx's optional configuration file, ~/.config/teryx/config (or the
+// equivalent under $XDG_CONFIG_HOME).
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// configEntry is one top-level key of the configuration file. A key is either
+// given a value on its own line ("key: value") or, with nothing after the
+// colon, followed by a list of "- item" lines.
+type configEntry struct {
+	Line  int
+	Key   string
+	Value string
+	List  []string
+}
+
+// teryxConfig holds the settings read from the configuration file.
+type teryxConfig struct {
+	// BaseDir replaces $HOME/fossils as the root of the clone layout.
+	BaseDir string
+	// Layout is "nested" (<base>/<host>/<path>, the default) or "flat"
+	// (<base>/<name>).
+	Layout string
+	// Repos lists the URLs that 'teryx clone-all' clones.
+	Repos []string
+}
+
+// configLineError is a problem with one line of the configuration file.
+type configLineError struct {
+	Line int
+	Err  error
+}
+
+func (e *configLineError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+
+func (e *configLineError) Unwrap() error { return e.Err }
+
+// configPath returns the path of the configuration file, which need not exist.
+func configPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not locate the user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "teryx", "config"), nil
+}
+
+// parseConfig reads the entries of a configuration file. Blank lines and lines
+// starting with '#' are ignored; anything else that isn't a "key: value" line
+// or a list item under a key is an error naming the line.
+func parseConfig(content string) ([]configEntry, error) {
+	var entries []configEntry
+	for i, line := range strings.Split(content, "\n") {
+		lineNumber := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			if len(entries) == 0 || entries[len(entries)-1].Value != "" {
+				return nil, &configLineError{lineNumber, errors.New("list item outside of a list")}
+			}
+			last := &entries[len(entries)-1]
+			last.List = append(last.List, strings.TrimSpace(item))
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok || line != strings.TrimLeft(line, " \t") || strings.ContainsAny(key, " \t") {
+			return nil, &configLineError{lineNumber, fmt.Errorf("expected 'key: value', got '%s'", trimmed)}
+		}
+		entries = append(entries, configEntry{Line: lineNumber, Key: key, Value: strings.TrimSpace(value)})
+	}
+	return entries, nil
+}
+
+// expandHome replaces a leading "~/" in path with the user's home directory.
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// loadConfig reads the configuration file. A missing file yields the zero
+// configuration; unknown keys are ignored.
+func loadConfig() (teryxConfig, error) {
+	var config teryxConfig
+	path, err := configPath()
+	if err != nil {
+		return config, err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	entries, err := parseConfig(string(content))
+	if err != nil {
+		return config, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, entry := range entries {
+		switch entry.Key {
+		case "base-dir":
+			if config.BaseDir, err = expandHome(entry.Value); err != nil {
+				return config, fmt.Errorf("%s: %w", path, &configLineError{entry.Line, err})
+			}
+		case "layout":
+			if entry.Value != "nested" && entry.Value != "flat" {
+				return config, fmt.Errorf("%s: %w", path, &configLineError{entry.Line, fmt.Errorf("layout must be 'nested' or 'flat', not '%s'", entry.Value)})
+			}
+			config.Layout = entry.Value
+		case "repos":
+			if entry.Value != "" {
+				return config, fmt.Errorf("%s: %w", path, &configLineError{entry.Line, errors.New("repos must be a list of '- <url>' lines")})
+			}
+			config.Repos = entry.List
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,80 @@
+// configuressh.go
+//
+// Implements 'teryx configure-ssh', which manages fossil's global ssh-command
+// setting so ssh:// clones and syncs use a chosen key and port.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// configureSSHCmd handles the 'teryx configure-ssh' command.
+var configureSSHCmd = &cobra.Command{
+	Use:   "configure-ssh",
+	Short: "Sets fossil's global ssh-command to use a given identity file and port.",
+	Long: `Sets fossil's global 'ssh-command' setting, which fossil uses for all ssh://
+clones and syncs, to an ssh invocation with the given identity file and/or port.
+The identity file is stored as an absolute path, so syncs from any directory
+find it.
+Use --reset to remove the setting and go back to fossil's default.`,
+	Args: cobra.NoArgs,
+	PreRunE: rejectFlagConflicts(nil,
+		[2]string{"reset", "identity-file"},
+		[2]string{"reset", "port"},
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		identityFile, _ := cmd.Flags().GetString("identity-file")
+		port, _ := cmd.Flags().GetInt("port")
+		reset, _ := cmd.Flags().GetBool("reset")
+
+		if reset {
+			if err := executeCommand("", "fossil", "unset", "ssh-command", "--global"); err != nil {
+				log.Fatalf("❌ Failed to reset ssh-command: %v", err)
+			}
+			fmt.Println("✅ Success! ssh-command reset to fossil's default.")
+			return
+		}
+
+		if identityFile == "" && port == 0 {
+			log.Fatal("❌ Specify --identity-file and/or --port, or --reset to clear the setting.")
+		}
+		if identityFile != "" {
+			// The setting is global, so a relative path would only work
+			// from the directory configure-ssh ran in.
+			var err error
+			if identityFile, err = filepath.Abs(identityFile); err != nil {
+				log.Fatalf("❌ Invalid --identity-file path: %v", err)
+			}
+			if err := validateIdentityFile(identityFile); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+		if port < 0 || port > 65535 {
+			log.Fatalf("❌ Invalid port %d.", port)
+		}
+
+		sshCommand := fossilSSHCommand(identityFile, port)
+		if err := executeCommand("", "fossil", "settings", "ssh-command", sshCommand, "--global"); err != nil {
+			log.Fatalf("❌ Failed to set ssh-command: %v", err)
+		}
+
+		// Show the setting as fossil now reports it.
+		if err := executeCommand("", "fossil", "settings", "ssh-command", "--global"); err != nil {
+			log.Fatalf("❌ Failed to read back ssh-command: %v", err)
+		}
+		fmt.Printf("✅ Success! Fossil will now use: %s\n", sshCommand)
+	},
+}
+
+func init() {
+	configureSSHCmd.Flags().StringP("identity-file", "i", "", "SSH private key for fossil to use")
+	configureSSHCmd.Flags().IntP("port", "p", 0, "SSH port for fossil to connect to")
+	configureSSHCmd.Flags().Bool("reset", false, "Remove the ssh-command setting")
+
+	rootCmd.AddCommand(configureSSHCmd)
+}
@@ -0,0 +1,209 @@
+// diff.go
+//
+// Implements 'teryx diff', a wrapper around 'fossil diff' for the open checkout
+// with an optional diffstat summary, word diff or pre-commit check.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// diffFileStat counts the lines added and removed in one file of a diff.
+type diffFileStat struct {
+	Path       string
+	Insertions int
+	Deletions  int
+}
+
+// internalDiffArgs are passed to every 'fossil diff' whose output teryx
+// parses: -i bypasses any external diff-command, so the output is always a
+// unified diff, and -N includes the full text of added and deleted files.
+var internalDiffArgs = []string{"-i", "-N"}
+
+// parseDiffStat tallies the insertions and deletions per file in the unified
+// diff output of 'fossil diff', where each file starts with an "Index: <path>"
+// line.
+func parseDiffStat(diff string) []diffFileStat {
+	var stats []diffFileStat
+	inHeader := false
+	for _, line := range strings.Split(diff, "\n") {
+		if path, ok := strings.CutPrefix(line, "Index: "); ok {
+			stats = append(stats, diffFileStat{Path: path})
+			inHeader = true
+			continue
+		}
+		// Everything up to the first hunk ("@@ ...") is the file header, whose
+		// "--- a" / "+++ b" lines must not be counted as changes.
+		if strings.HasPrefix(line, "@@") {
+			inHeader = false
+			continue
+		}
+		if len(stats) == 0 || inHeader {
+			continue
+		}
+		current := &stats[len(stats)-1]
+		switch {
+		case strings.HasPrefix(line, "+"):
+			current.Insertions++
+		case strings.HasPrefix(line, "-"):
+			current.Deletions++
+		}
+	}
+	return stats
+}
+
+// printDiffStat prints stats as a table followed by a git-style summary line.
+func printDiffStat(stats []diffFileStat) {
+	if len(stats) == 0 {
+		fmt.Println("ℹ️  No changes.")
+		return
+	}
+
+	pathWidth := 0
+	for _, stat := range stats {
+		pathWidth = max(pathWidth, len(stat.Path))
+	}
+
+	totalInsertions, totalDeletions := 0, 0
+	for _, stat := range stats {
+		fmt.Printf(" %-*s | %6s %6s\n", pathWidth, stat.Path,
+			fmt.Sprintf("+%d", stat.Insertions), fmt.Sprintf("-%d", stat.Deletions))
+		totalInsertions += stat.Insertions
+		totalDeletions += stat.Deletions
+	}
+	fmt.Printf(" %d files changed, %d insertions(+), %d deletions(-)\n", len(stats), totalInsertions, totalDeletions)
+}
+
+// stashIDPattern matches the first line of each entry in 'fossil stash list',
+// e.g. "   2: [5f3a8e1b43] on 2024-01-01 12:00:00".
+var stashIDPattern = regexp.MustCompile(`^\s*(\d+):`)
+
+// stashIDs returns the IDs of the open checkout's stash entries, newest first
+// as fossil lists them.
+func stashIDs() ([]string, error) {
+	output, err := captureCommand("", "fossil", "stash", "list")
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if match := stashIDPattern.FindStringSubmatch(line); match != nil {
+			ids = append(ids, match[1])
+		}
+	}
+	return ids, nil
+}
+
+// latestStash is the --stash value meaning the most recent stash entry, used
+// when the flag is given without an ID.
+const latestStash = "latest"
+
+// diffCmd handles the 'teryx diff' command.
+var diffCmd = &cobra.Command{
+	Use:   "diff [file...]",
+	Short: "Shows the uncommitted changes in the open checkout.",
+	Long: `Wraps 'fossil diff' for the open checkout, optionally limited to the given
+files. With --stat, prints a table of the lines added and removed per file
+instead of the full diff.
+
+--stash shows the changes saved in a stash entry instead, so they can be
+reviewed before 'fossil stash pop' or 'apply'. Without an ID, as in
+'teryx diff --stash', the most recent entry is shown; 'teryx diff --stash 2'
+shows entry 2.
+
+--check doesn't show the diff, but scans the lines it adds for problems that
+shouldn't be committed, printing each as "file:line: problem" and exiting
+non-zero if there are any, so it can serve as a pre-commit guard. The checks
+are "whitespace" (trailing spaces or tabs), "conflicts" (merge conflict
+markers such as "<<<<<<<") and "binary" (binary files larger than
+--max-binary-kb); --checks selects which to run, e.g. --checks conflicts,binary.
+
+--word-diff shows changed lines word by word instead, which reads better for
+prose and config files: removed words appear as [-old-] and added ones as
+{+new+}, within the unchanged rest of the line. It also works with --stash.`,
+	PreRunE: rejectFlagConflicts(checkoutPreRun,
+		[2]string{"check", "stat"},
+		[2]string{"check", "stash"},
+		[2]string{"word-diff", "stat"},
+		[2]string{"word-diff", "check"},
+	),
+	Run: func(cmd *cobra.Command, args []string) {
+		stat, _ := cmd.Flags().GetBool("stat")
+		stash, _ := cmd.Flags().GetString("stash")
+		check, _ := cmd.Flags().GetBool("check")
+		words, _ := cmd.Flags().GetBool("word-diff")
+
+		if check {
+			checks, _ := cmd.Flags().GetStringSlice("checks")
+			maxBinaryKB, _ := cmd.Flags().GetInt64("max-binary-kb")
+			runDiffCheck(args, checks, maxBinaryKB)
+			return
+		}
+
+		diffArgs := append([]string{"diff"}, args...)
+		if cmd.Flags().Changed("stash") {
+			// "--stash 2" leaves 2 as an argument, since the ID is optional.
+			if stash == latestStash && len(args) == 1 && args[0] != "" && strings.Trim(args[0], "0123456789") == "" {
+				stash, args = args[0], nil
+			}
+			if len(args) > 0 {
+				log.Fatal("❌ --stash shows a whole stash entry and can't be limited to files.")
+			}
+			ids, err := stashIDs()
+			if err != nil {
+				log.Fatalf("❌ Failed to list the stash: %v", err)
+			}
+			if len(ids) == 0 {
+				fmt.Println("ℹ️  The stash is empty.")
+				return
+			}
+			if stash == latestStash {
+				stash = ids[0]
+			} else if !slices.Contains(ids, stash) {
+				log.Fatalf("❌ There is no stash entry %s; 'fossil stash list' shows the existing ones.", stash)
+			}
+			diffArgs = []string{"stash", "diff", stash}
+		}
+
+		if words {
+			output, err := captureCommand("", "fossil", append(append([]string{}, diffArgs...), internalDiffArgs...)...)
+			if err != nil {
+				log.Fatalf("❌ Failed to compute diff: %v", err)
+			}
+			fmt.Print(wordDiff(string(output)))
+			return
+		}
+		if !stat {
+			if err := executeCommand("", "fossil", diffArgs...); err != nil {
+				log.Fatalf("❌ Failed to show diff: %v", err)
+			}
+			return
+		}
+
+		fossilArgs := append(append([]string{}, diffArgs...), internalDiffArgs...)
+		output, err := captureCommand("", "fossil", fossilArgs...)
+		if err != nil {
+			log.Fatalf("❌ Failed to compute diff: %v", err)
+		}
+		printDiffStat(parseDiffStat(string(output)))
+	},
+}
+
+func init() {
+	diffCmd.Flags().Bool("stat", false, "Summarize the insertions and deletions per file")
+	diffCmd.Flags().String("stash", "", "Show the changes in this stash entry (the most recent when no ID is given)")
+	diffCmd.Flags().Lookup("stash").NoOptDefVal = latestStash
+	diffCmd.Flags().Bool("word-diff", false, "Show changes word by word, as [-removed-] and {+added+}")
+	diffCmd.Flags().Bool("check", false, "Check the added lines for trailing whitespace, conflict markers and large binaries instead of showing them")
+	diffCmd.Flags().StringSlice("checks", diffChecks, "Checks to run with --check (whitespace, conflicts, binary)")
+	diffCmd.Flags().Int64("max-binary-kb", 1024, "Size in KiB above which --check reports a binary file (0 to allow any size)")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiffStat(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []diffFileStat
+	}{
+		{"no changes", "", nil},
+		{"one file",
+			"Index: a.txt\n==================================================================\n--- a.txt\n+++ a.txt\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n+two and a half\n three\n",
+			[]diffFileStat{{"a.txt", 2, 1}}},
+		{"header lines are not counted",
+			"Index: a.txt\n--- a.txt\n+++ a.txt\n@@ -1 +1 @@\n-a\n+b\n",
+			[]diffFileStat{{"a.txt", 1, 1}}},
+		{"several files and hunks",
+			"Index: a.txt\n--- a.txt\n+++ a.txt\n@@ -1 +1 @@\n-a\n+b\n@@ -10 +10,2 @@\n x\n+y\n" +
+				"Index: dir/new.txt\n--- dir/new.txt\n+++ dir/new.txt\n@@ -0,0 +1,2 @@\n+1\n+2\n" +
+				"Index: gone.txt\n--- gone.txt\n+++ gone.txt\n@@ -1 +0,0 @@\n-bye\n",
+			[]diffFileStat{{"a.txt", 2, 1}, {"dir/new.txt", 2, 0}, {"gone.txt", 0, 1}}},
+		{"binary file without hunks",
+			"Index: logo.png\n==================================================================\ncannot compute difference between binary files\n",
+			[]diffFileStat{{"logo.png", 0, 0}}},
+		{"changed lines that look like headers",
+			"Index: a.txt\n--- a.txt\n+++ a.txt\n@@ -1 +1 @@\n--- old rule\n+++ new rule\n",
+			[]diffFileStat{{"a.txt", 1, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDiffStat(tt.diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDiffStat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
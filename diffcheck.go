@@ -0,0 +1,144 @@
+// diffcheck.go
+//
+// Implements 'teryx diff --check', which scans the pending changes for common
+// mistakes so it can guard commits, e.g. from a pre-commit hook.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// diffChecks are the checks 'teryx diff --check' knows, all run by default.
+var diffChecks = []string{"whitespace", "conflicts", "binary"}
+
+// diffIssue is a problem found in the pending changes. Line is the line
+// number in the new version of the file, or 0 for the file as a whole.
+type diffIssue struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+// hunkHeaderPattern matches a unified diff hunk header and captures the first
+// line number of the new version, e.g. "@@ -10,4 +12,6 @@".
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// isConflictMarker reports whether line is a merge conflict marker: seven
+// '<', '=' or '>' followed by the end of the line or a space, as written by
+// fossil ("<<<<<<< BEGIN MERGE CONFLICT") and other tools.
+func isConflictMarker(line string) bool {
+	for _, marker := range []string{"<<<<<<<", "=======", ">>>>>>>"} {
+		if rest, ok := strings.CutPrefix(line, marker); ok && (rest == "" || rest[0] == ' ') {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDiff scans the lines added in a unified diff, as produced by 'fossil
+// diff' with internalDiffArgs, for trailing whitespace and conflict markers,
+// depending on which of those checks are enabled. It also returns the files
+// fossil could not diff because they are binary.
+func checkDiff(diff string, whitespace, conflicts bool) ([]diffIssue, []string) {
+	var issues []diffIssue
+	var binaries []string
+	path, lineNumber, inHunk := "", 0, false
+	for _, line := range strings.Split(diff, "\n") {
+		if name, ok := strings.CutPrefix(line, "Index: "); ok {
+			path, lineNumber, inHunk = name, 0, false
+			continue
+		}
+		if match := hunkHeaderPattern.FindStringSubmatch(line); match != nil {
+			lineNumber, _ = strconv.Atoi(match[1])
+			inHunk = true
+			continue
+		}
+		if !inHunk {
+			if path != "" && strings.Contains(strings.ToLower(line), "binary files") {
+				binaries = append(binaries, path)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "+"):
+			added := strings.TrimSuffix(line[1:], "\r")
+			if whitespace && strings.TrimRight(added, " \t") != added {
+				issues = append(issues, diffIssue{path, lineNumber, "trailing whitespace"})
+			}
+			if conflicts && isConflictMarker(added) {
+				issues = append(issues, diffIssue{path, lineNumber, "merge conflict marker"})
+			}
+			lineNumber++
+		case strings.HasPrefix(line, "-"):
+		default:
+			lineNumber++
+		}
+	}
+	return issues, binaries
+}
+
+// checkBinarySizes reports the binary files, relative to the checkout root,
+// that are larger than maxSize bytes. Deleted files are skipped.
+func checkBinarySizes(root string, binaries []string, maxSize int64) []diffIssue {
+	var issues []diffIssue
+	for _, path := range binaries {
+		info, err := os.Stat(filepath.Join(root, path))
+		if err != nil || info.Size() <= maxSize {
+			continue
+		}
+		issues = append(issues, diffIssue{path, 0, fmt.Sprintf("binary file of %s exceeds the limit of %s", formatBytes(info.Size()), formatBytes(maxSize))})
+	}
+	return issues
+}
+
+// runDiffCheck runs the enabled checks over the pending changes of the open
+// checkout, limited to files if any are given, prints each issue as
+// "path:line: problem" and exits non-zero if there are any. Binary files are
+// only reported when larger than maxBinaryKB kibibytes; 0 disables that check.
+func runDiffCheck(files, checks []string, maxBinaryKB int64) {
+	for _, check := range checks {
+		if !slices.Contains(diffChecks, check) {
+			log.Fatalf("❌ Unknown check '%s'; expected one of: %s.", check, strings.Join(diffChecks, ", "))
+		}
+	}
+	if maxBinaryKB < 0 {
+		log.Fatal("❌ --max-binary-kb can't be negative.")
+	}
+
+	root, err := findCheckoutRoot("")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fossilArgs := append(append([]string{"diff"}, files...), internalDiffArgs...)
+	output, err := captureCommand("", "fossil", fossilArgs...)
+	if err != nil {
+		log.Fatalf("❌ Failed to compute diff: %v", err)
+	}
+
+	issues, binaries := checkDiff(string(output), slices.Contains(checks, "whitespace"), slices.Contains(checks, "conflicts"))
+	if slices.Contains(checks, "binary") && maxBinaryKB > 0 {
+		issues = append(issues, checkBinarySizes(root, binaries, maxBinaryKB*1024)...)
+	}
+	if len(issues) == 0 {
+		fmt.Println("✅ No issues found in the pending changes.")
+		return
+	}
+
+	for _, issue := range issues {
+		if issue.Line > 0 {
+			fmt.Printf("⚠️ %s:%d: %s\n", issue.Path, issue.Line, issue.Message)
+		} else {
+			fmt.Printf("⚠️ %s: %s\n", issue.Path, issue.Message)
+		}
+	}
+	log.Fatalf("❌ Found %d issues in the pending changes.", len(issues))
+}
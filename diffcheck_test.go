@@ -0,0 +1,86 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsConflictMarker(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"<<<<<<< BEGIN MERGE CONFLICT: local copy shown first", true},
+		{"=======", true},
+		{">>>>>>> END MERGE CONFLICT", true},
+		{"======= COPY OF ORIGINAL", true},
+		{"==================================================================", false},
+		{"<<<<<<<<", false},
+		{" =======", false},
+		{"a = b", false},
+	}
+	for _, tt := range tests {
+		if got := isConflictMarker(tt.line); got != tt.want {
+			t.Errorf("isConflictMarker(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDiff(t *testing.T) {
+	header := "Index: f.txt\n==================================================================\n--- f.txt\n+++ f.txt\n"
+	tests := []struct {
+		name         string
+		diff         string
+		wantIssues   []diffIssue
+		wantBinaries []string
+	}{
+		{"clean change", header + "@@ -1,2 +1,2 @@\n ctx\n-old\n+new\n", nil, nil},
+		{"line numbers across hunks",
+			header + "@@ -1,3 +1,3 @@\n one\n-two\n+two \n three\n@@ -10,2 +20,3 @@\n ten\n+added\t\n eleven\n",
+			[]diffIssue{{"f.txt", 2, "trailing whitespace"}, {"f.txt", 21, "trailing whitespace"}}, nil},
+		{"removed lines don't count",
+			header + "@@ -5,3 +5,2 @@\n-gone \n-gone too\n+kept \n",
+			[]diffIssue{{"f.txt", 5, "trailing whitespace"}}, nil},
+		{"CRLF line endings are not trailing whitespace",
+			header + "@@ -1 +1 @@\r\n-old\r\n+new\r\n",
+			nil, nil},
+		{"whitespace before CRLF is",
+			header + "@@ -1 +1 @@\r\n-old\r\n+new \r\n",
+			[]diffIssue{{"f.txt", 1, "trailing whitespace"}}, nil},
+		{"conflict marker added inside a hunk",
+			header + "@@ -1,2 +1,5 @@\n+<<<<<<< BEGIN MERGE CONFLICT\n+a\n+=======\n+b\n+>>>>>>> END MERGE CONFLICT\n",
+			[]diffIssue{
+				{"f.txt", 1, "merge conflict marker"},
+				{"f.txt", 3, "merge conflict marker"},
+				{"f.txt", 5, "merge conflict marker"},
+			}, nil},
+		{"marker as a context line is not added",
+			header + "@@ -1,2 +1,2 @@\n =======\n-a\n+b\n",
+			nil, nil},
+		{"separator outside a hunk is a header",
+			"Index: f.txt\n=======\n--- f.txt\n+++ f.txt\n@@ -1 +1 @@\n-a\n+b\n",
+			nil, nil},
+		{"binary file",
+			"Index: logo.png\n==================================================================\ncannot compute difference between binary files\n" +
+				header + "@@ -1 +1 @@\n-a\n+b\n",
+			nil, []string{"logo.png"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, binaries := checkDiff(tt.diff, true, true)
+			if !reflect.DeepEqual(issues, tt.wantIssues) {
+				t.Errorf("issues = %v, want %v", issues, tt.wantIssues)
+			}
+			if !reflect.DeepEqual(binaries, tt.wantBinaries) {
+				t.Errorf("binaries = %v, want %v", binaries, tt.wantBinaries)
+			}
+		})
+	}
+}
+
+func TestCheckDiffDisabledChecks(t *testing.T) {
+	diff := "Index: f.txt\n@@ -1 +1,2 @@\n+a \n+=======\n"
+	if issues, _ := checkDiff(diff, false, false); issues != nil {
+		t.Errorf("checkDiff with no checks enabled = %v, want none", issues)
+	}
+}
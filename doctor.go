@@ -0,0 +1,193 @@
+// doctor.go
+//
+// Implements 'teryx doctor', which checks the local setup teryx depends on and,
+// with --fix, offers to correct the problems it knows how to fix.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorFinding is a problem found by 'teryx doctor'. Fix is nil for problems
+// teryx can't correct itself; otherwise FixPrompt describes what it does.
+type doctorFinding struct {
+	Check     string
+	Message   string
+	FixPrompt string
+	Fix       func() error
+}
+
+// commentOutLine turns line lineNumber (1-based) of the file at path into a
+// comment, keeping its text so it can be repaired by hand later.
+func commentOutLine(path string, lineNumber int) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	if lineNumber < 1 || lineNumber > len(lines) {
+		return fmt.Errorf("%s has no line %d", path, lineNumber)
+	}
+	lines[lineNumber-1] = "# " + lines[lineNumber-1]
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// doctorFossil checks that fossil is installed.
+func doctorFossil() []doctorFinding {
+	if _, err := exec.LookPath("fossil"); err != nil {
+		return []doctorFinding{{Check: "fossil", Message: "fossil is not on your PATH; install it from https://fossil-scm.org"}}
+	}
+	return nil
+}
+
+// doctorConfig checks that the config file, if any, can be read. A malformed
+// line can be commented out.
+func doctorConfig() []doctorFinding {
+	_, err := loadConfig()
+	if err == nil {
+		return nil
+	}
+	finding := doctorFinding{Check: "config", Message: err.Error()}
+	var lineErr *configLineError
+	if path, pathErr := configPath(); pathErr == nil && errors.As(err, &lineErr) {
+		finding.FixPrompt = fmt.Sprintf("Comment out line %d of %s?", lineErr.Line, path)
+		finding.Fix = func() error { return commentOutLine(path, lineErr.Line) }
+	}
+	return []doctorFinding{finding}
+}
+
+// doctorBaseDir checks that the root of the clone layout exists.
+func doctorBaseDir() []doctorFinding {
+	baseDir, err := fossilsBaseDir()
+	if err != nil {
+		// A broken config file is already reported by doctorConfig.
+		return nil
+	}
+	if info, err := os.Stat(baseDir); err == nil && info.IsDir() {
+		return nil
+	} else if err == nil {
+		return []doctorFinding{{Check: "base-dir", Message: fmt.Sprintf("%s is not a directory", baseDir)}}
+	}
+	return []doctorFinding{{
+		Check:     "base-dir",
+		Message:   fmt.Sprintf("the clone directory %s does not exist", baseDir),
+		FixPrompt: fmt.Sprintf("Create %s?", baseDir),
+		Fix:       func() error { return os.MkdirAll(baseDir, 0755) },
+	}}
+}
+
+// doctorSSHCommand checks that an identity file named in fossil's global
+// ssh-command setting exists and, as 'teryx configure-ssh' writes it, is
+// given by an absolute path.
+func doctorSSHCommand() []doctorFinding {
+	if _, err := exec.LookPath("fossil"); err != nil {
+		return nil
+	}
+	sshCommand, ok, err := globalSetting("ssh-command")
+	if err != nil {
+		return []doctorFinding{{Check: "ssh-command", Message: fmt.Sprintf("could not read the ssh-command setting: %v", err)}}
+	}
+	if !ok {
+		return nil
+	}
+	identityFile, ok := sshCommandIdentityFile(sshCommand)
+	if !ok {
+		return nil
+	}
+	unset := func() error {
+		_, err := captureCommand("", "fossil", "unset", "ssh-command", "--global")
+		return err
+	}
+	expanded, _ := expandHome(identityFile)
+	if !filepath.IsAbs(expanded) {
+		return []doctorFinding{{
+			Check:     "ssh-command",
+			Message:   fmt.Sprintf("the global ssh-command uses the relative identity file %s, which only works from one directory; set it again with 'teryx configure-ssh -i'", identityFile),
+			FixPrompt: "Remove the ssh-command setting, so fossil uses plain ssh again?",
+			Fix:       unset,
+		}}
+	}
+	if _, err := os.Stat(expanded); err != nil {
+		return []doctorFinding{{
+			Check:     "ssh-command",
+			Message:   fmt.Sprintf("the global ssh-command uses the identity file %s, which does not exist", identityFile),
+			FixPrompt: "Remove the ssh-command setting, so fossil uses plain ssh again?",
+			Fix:       unset,
+		}}
+	}
+	return nil
+}
+
+// doctorCmd handles the 'teryx doctor' command.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Checks the local setup for common problems, optionally fixing them.",
+	Long: `Checks that fossil is installed, that the config file can be read, that the
+clone directory (base-dir, or ~/fossils) exists, and that an identity file set
+with 'teryx configure-ssh' is still there.
+
+With --fix, teryx offers to correct each problem it can fix, asking first:
+it creates a missing clone directory, comments out a malformed line of the
+config file, and removes an ssh-command setting whose key is gone. Other
+problems are reported as usual. The exit status is non-zero while problems
+remain.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		if fix && !stdinIsTerminal() {
+			log.Fatal("❌ --fix asks before changing anything, so it must be run interactively.")
+		}
+
+		var findings []doctorFinding
+		for _, check := range []func() []doctorFinding{doctorFossil, doctorConfig, doctorBaseDir, doctorSSHCommand} {
+			findings = append(findings, check()...)
+		}
+		if len(findings) == 0 {
+			fmt.Println("✅ No problems found.")
+			return
+		}
+
+		remaining := 0
+		for _, finding := range findings {
+			fmt.Printf("⚠️ %s: %s\n", finding.Check, finding.Message)
+			switch {
+			case !fix || finding.Fix == nil:
+				remaining++
+			case !confirm(finding.FixPrompt):
+				fmt.Println("⏭️  Left as is.")
+				remaining++
+			default:
+				if err := finding.Fix(); err != nil {
+					fmt.Printf("❌ Fix failed: %v\n", err)
+					remaining++
+				} else {
+					fmt.Println("✅ Fixed.")
+				}
+			}
+		}
+
+		if remaining > 0 {
+			if !fix && slices.ContainsFunc(findings, func(f doctorFinding) bool { return f.Fix != nil }) {
+				fmt.Println("ℹ️  Run 'teryx doctor --fix' to correct the fixable problems.")
+			}
+			log.Fatalf("❌ %d problem(s) remain.", remaining)
+		}
+		fmt.Println("✅ All problems fixed. Run 'teryx doctor' again to check.")
+	},
+}
+
+func init() {
+	doctorCmd.Flags().Bool("fix", false, "Offer to fix the problems that can be fixed automatically")
+
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,95 @@
+// fork.go
+//
+// Implements 'teryx fork', which makes an independent copy of a local
+// repository file for experiments and opens a checkout of it.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// copyFile copies the contents of src to a new file at dst, failing if dst
+// already exists.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// forkCmd handles the 'teryx fork' command.
+var forkCmd = &cobra.Command{
+	Use:   "fork <source.fossil> <dest.fossil>",
+	Short: "Creates an independent copy of a local repository and opens it.",
+	Long: `Clones a local repository file into a new repository, opens a checkout of the
+copy next to it and clears the copy's remote-url, so it can never sync back to
+the original or its server. With --copy, the file is copied byte for byte
+instead of cloned (keeping e.g. private branches and unversioned files).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		source := args[0]
+		dest := args[1]
+		plainCopy, _ := cmd.Flags().GetBool("copy")
+
+		if _, err := os.Stat(source); err != nil {
+			log.Fatalf("❌ Cannot read source repository: %v", err)
+		}
+		// Auto-append .fossil if not present, as init does
+		if !strings.HasSuffix(dest, ".fossil") {
+			dest += ".fossil"
+			fmt.Printf("ℹ️  Appending .fossil extension. Fork will be: %s\n", dest)
+		}
+		if _, err := os.Stat(dest); err == nil {
+			log.Fatalf("❌ '%s' already exists.", dest)
+		}
+
+		fmt.Printf("🚀 Forking '%s' into '%s'...\n", source, dest)
+		if plainCopy {
+			if err := copyFile(source, dest); err != nil {
+				log.Fatalf("❌ Failed to copy repository: %v", err)
+			}
+		} else {
+			if err := executeCommand("", "fossil", "clone", source, dest); err != nil {
+				log.Fatalf("❌ Failed to clone repository: %v", err)
+			}
+		}
+
+		// Detach the fork from wherever the source syncs to.
+		if err := executeCommand("", "fossil", "remote", "off", "-R", dest); err != nil {
+			log.Fatalf("❌ Failed to clear the fork's remote-url: %v", err)
+		}
+
+		checkoutDir, err := openRepoCheckout(filepath.Dir(dest), filepath.Base(dest))
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		fmt.Printf("✅ Success! Fork opened in: %s\n", checkoutDir)
+	},
+}
+
+func init() {
+	forkCmd.Flags().Bool("copy", false, "Copy the repository file as is instead of cloning it")
+
+	rootCmd.AddCommand(forkCmd)
+}
@@ -2,9 +2,13 @@ module teryx
 
 go 1.24.4
 
-require github.com/spf13/cobra v1.9.1
+require (
+	github.com/fsnotify/fsnotify v1.10.1
+	github.com/spf13/cobra v1.9.1
+	github.com/spf13/pflag v1.0.6
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.6 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 )
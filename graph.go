@@ -0,0 +1,167 @@
+// graph.go
+//
+// Implements 'teryx graph', which draws recent check-ins as an ASCII graph of
+// their branches and merges, similar to 'git log --graph'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// checkinParents returns the parents of each of the given check-ins, keyed by
+// full hash, with the primary parent first. fossil timeline can't print
+// parents, so they are read from the repository's plink table.
+func checkinParents(hashes []string) (map[string][]string, error) {
+	quoted := make([]string, len(hashes))
+	for i, hash := range hashes {
+		// Hashes come from fossil itself, but make sure nothing else slips in.
+		if strings.Trim(hash, "0123456789abcdef") != "" {
+			return nil, fmt.Errorf("unexpected check-in hash '%s'", hash)
+		}
+		quoted[i] = "'" + hash + "'"
+	}
+	query := "SELECT c.uuid || ' ' || p.uuid FROM plink" +
+		" JOIN blob c ON c.rid = plink.cid JOIN blob p ON p.rid = plink.pid" +
+		" WHERE c.uuid IN (" + strings.Join(quoted, ",") + ")" +
+		" ORDER BY plink.isprim DESC"
+	output, err := captureCommand("", "fossil", "sql", "--readonly", query)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		child, parent, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if ok {
+			parents[child] = append(parents[child], parent)
+		}
+	}
+	return parents, nil
+}
+
+// renderGraph draws entries, newest first, as rows of lanes: '*' marks the
+// check-in of the row and '|' the other lines of descent still open. Between
+// rows, '/' shows a line joining another and '\' a merge opening a new one.
+// Lines are drawn two characters apart, so connectors between distant lanes
+// are approximate.
+func renderGraph(entries []timelineEntry, parents map[string][]string) []string {
+	var rows []string
+	var lanes []string // the check-in each open line of descent leads to next
+	for _, entry := range entries {
+		col := slices.Index(lanes, entry.Hash)
+		if col < 0 {
+			lanes = append(lanes, entry.Hash)
+			col = len(lanes) - 1
+		}
+
+		row := make([]byte, 0, 2*len(lanes))
+		for i := range lanes {
+			if i == col {
+				row = append(row, '*', ' ')
+			} else {
+				row = append(row, '|', ' ')
+			}
+		}
+		rows = append(rows, fmt.Sprintf("%s%.10s %s [%s] %s", row, entry.Hash, entry.Date, entry.Branch, entry.Comment))
+
+		entryParents := parents[entry.Hash]
+		if len(entryParents) == 0 || slices.Contains(lanes, entryParents[0]) {
+			// The line ends here: at a root, or where it joins the line
+			// already leading to its parent. Lanes to its right move left.
+			if col < len(lanes)-1 || len(entryParents) > 0 {
+				connector := []byte(strings.Repeat(" ", 2*len(lanes)))
+				for i := range lanes {
+					switch {
+					case i < col:
+						connector[2*i] = '|'
+					case i > col:
+						connector[2*i-1] = '/'
+					case len(entryParents) == 0:
+					case slices.Index(lanes, entryParents[0]) < col:
+						// Joining a line to the left.
+						connector[2*i-1] = '/'
+					default:
+						// Joining a line to the right, which moves into
+						// this lane.
+						connector[2*i] = '|'
+					}
+				}
+				rows = append(rows, strings.TrimRight(string(connector), " "))
+			}
+			lanes = slices.Delete(lanes, col, col+1)
+		} else {
+			lanes[col] = entryParents[0]
+		}
+
+		// Merged-in parents that no line leads to yet get a new lane.
+		for _, parent := range entryParents[min(1, len(entryParents)):] {
+			if slices.Contains(lanes, parent) {
+				continue
+			}
+			lanes = append(lanes, parent)
+			connector := []byte(strings.Repeat(" ", 2*len(lanes)))
+			for i := range lanes[:len(lanes)-1] {
+				connector[2*i] = '|'
+			}
+			connector[2*len(lanes)-3] = '\\'
+			rows = append(rows, strings.TrimRight(string(connector), " "))
+		}
+	}
+	return rows
+}
+
+// graphCmd handles the 'teryx graph' command.
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Draws recent check-ins as an ASCII graph of branches and merges.",
+	Long: `Shows the most recent check-ins of the open checkout's repository, newest
+first, with an ASCII graph of how they descend from each other: '*' is a
+check-in, '|' a line of descent, '/' a line joining another at a fork, and '\'
+a merge. -n limits the number of check-ins and --branch shows only those on one
+branch. Nothing is changed.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		branch, _ := cmd.Flags().GetString("branch")
+
+		timelineArgs := []string{"-n", fmt.Sprint(limit)}
+		if branch != "" {
+			timelineArgs = append(timelineArgs, "--branch", branch)
+		}
+		entries, err := fetchTimeline(timelineArgs...)
+		if err != nil {
+			log.Fatalf("❌ Failed to read the timeline: %v", err)
+		}
+		if len(entries) == 0 {
+			fmt.Println("ℹ️  No check-ins to show.")
+			return
+		}
+
+		hashes := make([]string, len(entries))
+		for i, entry := range entries {
+			hashes[i] = entry.Hash
+		}
+		parents, err := checkinParents(hashes)
+		if err != nil {
+			log.Fatalf("❌ Failed to read check-in parents: %v", err)
+		}
+
+		for _, row := range renderGraph(entries, parents) {
+			fmt.Println(row)
+		}
+	},
+}
+
+func init() {
+	graphCmd.Flags().IntP("limit", "n", 20, "Number of check-ins to show")
+	graphCmd.Flags().StringP("branch", "b", "", "Only show check-ins on this branch")
+
+	rootCmd.AddCommand(graphCmd)
+}
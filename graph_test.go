@@ -0,0 +1,69 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderGraph(t *testing.T) {
+	entry := func(hash, comment string) timelineEntry {
+		return timelineEntry{Hash: hash, Date: "2024-01-01", Branch: "trunk", Comment: comment}
+	}
+	tests := []struct {
+		name    string
+		entries []timelineEntry
+		parents map[string][]string
+		want    string
+	}{
+		{"linear history",
+			[]timelineEntry{entry("c3", "third"), entry("c2", "second"), entry("c1", "first")},
+			map[string][]string{"c3": {"c2"}, "c2": {"c1"}},
+			`
+* c3 2024-01-01 [trunk] third
+* c2 2024-01-01 [trunk] second
+* c1 2024-01-01 [trunk] first`},
+		{"fork",
+			[]timelineEntry{entry("a2", "trunk"), entry("f1", "feature"), entry("a1", "root")},
+			map[string][]string{"a2": {"a1"}, "f1": {"a1"}},
+			`
+* a2 2024-01-01 [trunk] trunk
+| * f1 2024-01-01 [trunk] feature
+|/
+* a1 2024-01-01 [trunk] root`},
+		{"merge",
+			[]timelineEntry{entry("m1", "merge"), entry("a2", "trunk"), entry("f1", "feature"), entry("a1", "root")},
+			map[string][]string{"m1": {"a2", "f1"}, "a2": {"a1"}, "f1": {"a1"}},
+			`
+* m1 2024-01-01 [trunk] merge
+|\
+* | a2 2024-01-01 [trunk] trunk
+| * f1 2024-01-01 [trunk] feature
+|/
+* a1 2024-01-01 [trunk] root`},
+		{"merge with the merged branch newer",
+			[]timelineEntry{entry("m1", "merge"), entry("f1", "feature"), entry("a2", "trunk"), entry("a1", "root")},
+			map[string][]string{"m1": {"a2", "f1"}, "a2": {"a1"}, "f1": {"a1"}},
+			`
+* m1 2024-01-01 [trunk] merge
+|\
+| * f1 2024-01-01 [trunk] feature
+* | a2 2024-01-01 [trunk] trunk
+|/
+* a1 2024-01-01 [trunk] root`},
+		{"two roots",
+			[]timelineEntry{entry("b1", "other root"), entry("a1", "root")},
+			nil,
+			`
+* b1 2024-01-01 [trunk] other root
+* a1 2024-01-01 [trunk] root`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := strings.Split(strings.TrimPrefix(tt.want, "\n"), "\n")
+			if got := renderGraph(tt.entries, tt.parents); !reflect.DeepEqual(got, want) {
+				t.Errorf("renderGraph() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
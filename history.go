@@ -0,0 +1,272 @@
+// history.go
+//
+// Records the commands teryx runs that change repositories, checkouts or
+// settings, and implements 'teryx history', which shows that record.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// historyCommands are the commands recorded in the history file, by command
+// path. Commands that only read, and wrappers like batch and clone-all whose
+// work is done (and recorded) by teryx subprocesses, are left out.
+var historyCommands = []string{
+	"teryx amend",
+	"teryx bisect start", "teryx bisect good", "teryx bisect bad", "teryx bisect reset",
+	"teryx clone",
+	"teryx commit",
+	"teryx configure-ssh",
+	"teryx fork",
+	"teryx hooks install", "teryx hooks remove",
+	"teryx import-users",
+	"teryx init",
+	"teryx merge",
+	"teryx move-repo",
+	"teryx open",
+	"teryx prune-backups",
+	"teryx purge-clones",
+	"teryx reconcile-perms",
+	"teryx relink",
+	"teryx remote add", "teryx remote remove",
+	"teryx resync-config",
+	"teryx rm-checkout",
+	"teryx scrub",
+	"teryx set-user-default",
+	"teryx squash",
+	"teryx sync-all",
+	"teryx ticket add",
+	"teryx transfer",
+	"teryx update",
+	"teryx wiki commit",
+}
+
+// historyFlagCommands are commands that only read unless the given flag is
+// set, and are recorded only then, e.g. 'teryx doctor --fix'.
+var historyFlagCommands = map[string]string{
+	"teryx doctor": "fix",
+}
+
+// recordsHistory reports whether an invocation of cmd belongs in the history
+// file.
+func recordsHistory(cmd *cobra.Command) bool {
+	if slices.Contains(historyCommands, cmd.CommandPath()) {
+		return true
+	}
+	name, ok := historyFlagCommands[cmd.CommandPath()]
+	if !ok {
+		return false
+	}
+	set, _ := cmd.Flags().GetBool(name)
+	return set
+}
+
+// historyPath returns the location of the history file, next to the config
+// file.
+func historyPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not locate the user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "teryx", "history.log"), nil
+}
+
+// historyEntry is one line of the history file. Lines hold the fields
+// separated by tabs, in this order. Target is the repository file given with
+// -R or, failing that, the root of the checkout the command ran in, if any.
+type historyEntry struct {
+	Time    time.Time
+	Dir     string
+	Target  string
+	Command string
+}
+
+// historyArg quotes arg for the history file when it isn't a plain word.
+func historyArg(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789@%+=:,./_-") == "" {
+		return arg
+	}
+	return shellQuote(arg)
+}
+
+// historyCommandLine renders an invocation of cmd for the history file.
+// Passwords, given as flags or inside URLs, are masked.
+func historyCommandLine(cmd *cobra.Command, args []string) string {
+	parts := []string{cmd.CommandPath()}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		switch {
+		case strings.Contains(flag.Name, "password") && flag.Value.Type() == "string":
+			parts = append(parts, "--"+flag.Name+"=***")
+		case flag.Value.Type() == "bool" && flag.Value.String() == "true":
+			parts = append(parts, "--"+flag.Name)
+		default:
+			parts = append(parts, "--"+flag.Name+"="+historyArg(redactURL(flag.Value.String())))
+		}
+	})
+	for _, arg := range args {
+		parts = append(parts, historyArg(redactURL(arg)))
+	}
+	return strings.Join(parts, " ")
+}
+
+// recordHistory appends an invocation of cmd to the history file, noting the
+// working directory and the repository or checkout it applies to. The command
+// runs even if this fails.
+func recordHistory(cmd *cobra.Command, args []string) {
+	entry := historyEntry{Time: time.Now(), Command: historyCommandLine(cmd, args)}
+	entry.Dir, _ = os.Getwd()
+	if flag := cmd.Flags().Lookup("repository"); flag != nil && flag.Value.String() != "" {
+		entry.Target, _ = filepath.Abs(flag.Value.String())
+	} else if root, err := findCheckoutRoot(""); err == nil {
+		entry.Target = root
+	}
+
+	err := appendHistory(entry)
+	if err != nil && !quiet {
+		fmt.Fprintf(os.Stderr, "⚠️ Could not record this command in the history: %v\n", err)
+	}
+}
+
+// appendHistory writes entry to the end of the history file, creating it if
+// needed. The file is private to the user, as command lines can name hosts
+// and paths.
+func appendHistory(entry historyEntry) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	clean := strings.NewReplacer("\t", " ", "\n", " ")
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\t%s\n", entry.Time.Format(time.RFC3339),
+		clean.Replace(entry.Dir), clean.Replace(entry.Target), clean.Replace(entry.Command))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readHistory returns the entries of the history file, oldest first. A
+// missing file means no history yet; malformed lines are skipped.
+func readHistory() ([]historyEntry, error) {
+	path, err := historyPath()
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var entries []historyEntry
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		stamp, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{Time: stamp, Dir: fields[1], Target: fields[2], Command: fields[3]})
+	}
+	return entries, nil
+}
+
+// wrapHistoryCommands makes each command of historyCommands and
+// historyFlagCommands under root record itself in the history file before it
+// runs, once its flags and pre-run checks have passed.
+func wrapHistoryCommands(root *cobra.Command) {
+	for _, child := range root.Commands() {
+		wrapHistoryCommands(child)
+	}
+	_, flagged := historyFlagCommands[root.CommandPath()]
+	if run := root.Run; run != nil && (flagged || slices.Contains(historyCommands, root.CommandPath())) {
+		root.Run = func(cmd *cobra.Command, args []string) {
+			if recordsHistory(cmd) {
+				recordHistory(cmd, args)
+			}
+			run(cmd, args)
+		}
+	}
+}
+
+// historyCmd handles the 'teryx history' command.
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Shows the commands teryx has run that changed something.",
+	Long: `Lists the commands that changed repositories, checkouts or settings (init,
+clone, commit, update, transfer and the like), oldest first, with when and
+where they ran. They are recorded in history.log next to the config file, e.g.
+~/.config/teryx/history.log; passwords are masked.
+
+--repo shows only the commands whose repository file, checkout or working
+directory contains the given text, --since only those after a date (e.g. 2024-01-01) or
+age (e.g. 7d), and -n only the most recent ones.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, _ := cmd.Flags().GetString("repo")
+		since, _ := cmd.Flags().GetString("since")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		var after time.Time
+		if since != "" {
+			date, err := parseSince(since, time.Now())
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			after, _ = time.ParseInLocation("2006-01-02 15:04:05", date, time.Local)
+		}
+
+		entries, err := readHistory()
+		if err != nil {
+			log.Fatalf("❌ Failed to read the history: %v", err)
+		}
+		entries = slices.DeleteFunc(entries, func(entry historyEntry) bool {
+			if repo != "" && !strings.Contains(entry.Target, repo) && !strings.Contains(entry.Dir, repo) {
+				return true
+			}
+			return entry.Time.Before(after)
+		})
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+		if len(entries) == 0 {
+			fmt.Println("ℹ️  No recorded commands match.")
+			return
+		}
+
+		for _, entry := range entries {
+			where := entry.Target
+			if where == "" {
+				where = entry.Dir
+			}
+			fmt.Printf("%s  %s\n    in %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Command, where)
+		}
+	},
+}
+
+func init() {
+	historyCmd.Flags().String("repo", "", "Only show commands whose repository, checkout or directory contains this text")
+	historyCmd.Flags().String("since", "", "Only show commands after this date or age (e.g. 2024-01-01, 7d)")
+	historyCmd.Flags().IntP("limit", "n", 0, "Only show this many of the most recent commands (0 for all)")
+
+	rootCmd.AddCommand(historyCmd)
+}
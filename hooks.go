@@ -0,0 +1,119 @@
+// hooks.go
+//
+// Implements the 'teryx hooks' command group, which manages fossil's external
+// command hooks using git-style event names.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookEventTypes maps the event names accepted by 'teryx hooks install' to
+// fossil's hook types. Fossil's own names are accepted as well.
+var hookEventTypes = map[string]string{
+	"pre-commit":    "before-commit",
+	"before-commit": "before-commit",
+	"post-receive":  "after-receive",
+	"after-receive": "after-receive",
+}
+
+// hooksCmd is the parent of the 'teryx hooks' subcommands.
+var hooksCmd = &cobra.Command{
+	Use:               "hooks",
+	Short:             "Installs, lists and removes fossil command hooks.",
+	PersistentPreRunE: checkoutPreRun,
+}
+
+// hooksInstallCmd handles the 'teryx hooks install' command.
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install <event> <script>",
+	Short: "Runs a script on a repository event.",
+	Long: `Registers an external command hook with 'fossil hook add'. Supported events are
+pre-commit (fossil's before-commit) and post-receive (fossil's after-receive).
+
+The script path is made absolute. To pass fossil's substitutions, such as %F
+(the pending check-in manifest for before-commit hooks), quote a full command
+line instead of a bare path, e.g. "/path/check.sh %F".`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		event := args[0]
+		command := args[1]
+		sequence, _ := cmd.Flags().GetInt("sequence")
+
+		hookType, ok := hookEventTypes[event]
+		if !ok {
+			events := make([]string, 0, len(hookEventTypes))
+			for name := range hookEventTypes {
+				events = append(events, name)
+			}
+			sort.Strings(events)
+			log.Fatalf("❌ Unknown event '%s'. Supported events: %s", event, strings.Join(events, ", "))
+		}
+
+		// A bare script path is resolved now, since hooks don't run from the
+		// directory teryx was invoked in.
+		if !strings.ContainsAny(command, " \t") {
+			absPath, err := filepath.Abs(command)
+			if err != nil {
+				log.Fatalf("❌ Invalid script path: %v", err)
+			}
+			if _, err := os.Stat(absPath); err != nil {
+				log.Fatalf("❌ Cannot find hook script: %v", err)
+			}
+			command = absPath
+		}
+
+		fossilArgs := []string{"hook", "add", "--command", command, "--type", hookType}
+		if sequence != 0 {
+			fossilArgs = append(fossilArgs, "--sequence", fmt.Sprint(sequence))
+		}
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to install hook: %v", err)
+		}
+
+		fmt.Printf("✅ Success! '%s' will run on %s.\n", command, hookType)
+	},
+}
+
+// hooksListCmd handles the 'teryx hooks list' command.
+var hooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the installed hooks and their ids.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executeCommand("", "fossil", "hook", "list"); err != nil {
+			log.Fatalf("❌ Failed to list hooks: %v", err)
+		}
+	},
+}
+
+// hooksRemoveCmd handles the 'teryx hooks remove' command.
+var hooksRemoveCmd = &cobra.Command{
+	Use:   "remove <hook-id>",
+	Short: "Removes a hook by the id shown in 'teryx hooks list'.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executeCommand("", "fossil", "hook", "delete", args[0]); err != nil {
+			log.Fatalf("❌ Failed to remove hook: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Hook %s removed.\n", args[0])
+	},
+}
+
+func init() {
+	hooksInstallCmd.Flags().Int("sequence", 0, "Order in which this hook runs relative to others of the same type")
+
+	hooksCmd.AddCommand(hooksInstallCmd)
+	hooksCmd.AddCommand(hooksListCmd)
+	hooksCmd.AddCommand(hooksRemoveCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
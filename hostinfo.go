@@ -0,0 +1,214 @@
+// hostinfo.go
+//
+// Implements 'teryx hostinfo', a pre-flight check of a deployment target that
+// reports, over one ssh connection, what 'teryx transfer' and friends need to
+// know about the remote host.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// hostInfoWebUsers are the accounts web servers commonly run as, checked when
+// no running web server reveals its user.
+var hostInfoWebUsers = []string{"www-data", "apache", "nginx", "http", "www", "fossil"}
+
+// hostInfoDirs are the directories commonly used to keep served repositories,
+// checked for write access. "$HOME" is expanded on the remote host.
+var hostInfoDirs = []string{"/var/lib/fossil", "/srv/fossil", "/var/www/fossil", "/var/www", "/home/fossil", "$HOME/fossils", "$HOME"}
+
+// hostInfoScript is the shell script run on the remote host. It prints one
+// key=value line per finding, which parseHostInfo reads back.
+var hostInfoScript = strings.Join([]string{
+	`echo "os=$(uname -s)"`,
+	`echo "arch=$(uname -m)"`,
+	`echo "release=$( (. /etc/os-release && echo "$PRETTY_NAME") 2>/dev/null)"`,
+	`echo "user=$(id -un)"`,
+	`echo "fossil=$(fossil version 2>/dev/null | head -n 1)"`,
+	`echo "fossil_path=$(command -v fossil)"`,
+	`ps -eo user=,comm= 2>/dev/null | awk '$1 != "root" && $2 ~ /^(apache2|httpd|nginx|lighttpd|caddy|fossil)$/ { print "web_process=" $1 ":" $2 }' | sort -u`,
+	`for u in ` + strings.Join(hostInfoWebUsers, " ") + `; do id -u "$u" >/dev/null 2>&1 && echo "account=$u"; done`,
+	`for d in ` + strings.Join(hostInfoDirs, " ") + `; do [ -d "$d" ] && { [ -w "$d" ] && echo "writable=$d" || echo "readonly=$d"; }; done`,
+	`true`,
+}, "; ")
+
+// hostInfo is what 'teryx hostinfo' found out about a remote host.
+type hostInfo struct {
+	Host          string   `json:"host"`
+	Reachable     bool     `json:"reachable"`
+	Error         string   `json:"error,omitempty"`
+	LatencyMS     int64    `json:"latency_ms,omitempty"`
+	OS            string   `json:"os,omitempty"`
+	Arch          string   `json:"arch,omitempty"`
+	Release       string   `json:"release,omitempty"`
+	SSHUser       string   `json:"ssh_user,omitempty"`
+	FossilVersion string   `json:"fossil_version,omitempty"`
+	FossilPath    string   `json:"fossil_path,omitempty"`
+	WebUser       string   `json:"web_user,omitempty"`
+	WebServers    []string `json:"web_servers,omitempty"`
+	WebAccounts   []string `json:"web_accounts,omitempty"`
+	WritableDirs  []string `json:"writable_dirs,omitempty"`
+	ReadOnlyDirs  []string `json:"read_only_dirs,omitempty"`
+}
+
+// parseHostInfo fills info from the output of hostInfoScript. The web user is
+// that of the first running web server, or else the first common web server
+// account that exists.
+func parseHostInfo(info *hostInfo, output string) {
+	var webUsers []string
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || value == "" {
+			continue
+		}
+		switch key {
+		case "os":
+			info.OS = value
+		case "arch":
+			info.Arch = value
+		case "release":
+			info.Release = value
+		case "user":
+			info.SSHUser = value
+		case "fossil":
+			if match := fossilVersionPattern.FindStringSubmatch(value); match != nil {
+				info.FossilVersion = match[1] + "." + match[2]
+			} else {
+				info.FossilVersion = value
+			}
+		case "fossil_path":
+			info.FossilPath = value
+		case "web_process":
+			processUser, server, _ := strings.Cut(value, ":")
+			info.WebServers = append(info.WebServers, server+" (as "+processUser+")")
+			webUsers = append(webUsers, processUser)
+		case "account":
+			info.WebAccounts = append(info.WebAccounts, value)
+		case "writable":
+			info.WritableDirs = append(info.WritableDirs, value)
+		case "readonly":
+			info.ReadOnlyDirs = append(info.ReadOnlyDirs, value)
+		}
+	}
+	if len(webUsers) > 0 {
+		info.WebUser = webUsers[0]
+	} else if len(info.WebAccounts) > 0 {
+		info.WebUser = info.WebAccounts[0]
+	}
+}
+
+// probeHost connects to userHost over ssh, without prompting, and runs
+// hostInfoScript there.
+func probeHost(sshOpts []string, userHost string, timeout time.Duration) hostInfo {
+	info := hostInfo{Host: userHost}
+	sshArgs := append(append([]string{}, sshOpts...),
+		"-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", max(1, int(timeout.Seconds()))),
+		userHost, hostInfoScript)
+	start := time.Now()
+	output, err := captureCommand("", "ssh", sshArgs...)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	info.Reachable = true
+	info.LatencyMS = time.Since(start).Milliseconds()
+	parseHostInfo(&info, string(output))
+	return info
+}
+
+// hostInfoCmd handles the 'teryx hostinfo' command.
+var hostInfoCmd = &cobra.Command{
+	Use:   "hostinfo <user@host>",
+	Short: "Checks a remote host before cloning from or transferring to it.",
+	Long: `Connects to the host over ssh, without prompting for passwords, and reports in
+one go what deploying a repository there depends on:
+
+  - whether ssh works, and how long the round trip took
+  - the remote OS, architecture and distribution
+  - whether fossil is installed, where, and which version
+  - the web server user: the user of a running apache2, httpd, nginx,
+    lighttpd, caddy or fossil process, or else the first of the common
+    accounts (www-data, apache, nginx, http, www, fossil) that exists; pass
+    it to 'teryx transfer --remote-user'
+  - which of the usual repository directories (/var/lib/fossil, /srv/fossil,
+    /var/www/fossil, /var/www, /home/fossil, ~/fossils, ~) exist and are
+    writable by the ssh user
+
+The exit status is non-zero if the host can't be reached. --json prints the
+findings as an object.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		userHost := args[0]
+		asJSON, _ := cmd.Flags().GetBool("json")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		if strings.Contains(userHost, ":") {
+			log.Fatalf("❌ Give the host as user@host or host, without a path: '%s'.", userHost)
+		}
+		sshOpts, err := transferSSHOptions(cmd)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		info := probeHost(sshOpts, userHost, timeout)
+		if asJSON {
+			if err := printJSON(info); err != nil {
+				log.Fatalf("❌ Failed to write JSON: %v", err)
+			}
+			if !info.Reachable {
+				os.Exit(1)
+			}
+			return
+		}
+		if !info.Reachable {
+			log.Fatalf("❌ Could not connect to %s over ssh: %s", userHost, info.Error)
+		}
+
+		orNone := func(values ...string) string {
+			if joined := strings.Join(values, ", "); joined != "" {
+				return joined
+			}
+			return "(none)"
+		}
+		system := info.OS + " " + info.Arch
+		if info.Release != "" {
+			system += " (" + info.Release + ")"
+		}
+		fmt.Printf("✅ ssh to %s works (%d ms) as '%s'.\n", userHost, info.LatencyMS, info.SSHUser)
+		fmt.Printf("System:          %s\n", system)
+		if info.FossilPath != "" {
+			fmt.Printf("fossil:          %s (%s)\n", info.FossilVersion, info.FossilPath)
+		} else {
+			fmt.Println("fossil:          not installed (or not on the PATH of non-interactive shells)")
+		}
+		fmt.Printf("Web servers:     %s\n", orNone(info.WebServers...))
+		fmt.Printf("Web accounts:    %s\n", orNone(info.WebAccounts...))
+		fmt.Printf("Web user:        %s\n", orNone(info.WebUser))
+		fmt.Printf("Writable dirs:   %s\n", orNone(info.WritableDirs...))
+		fmt.Printf("Read-only dirs:  %s\n", orNone(info.ReadOnlyDirs...))
+
+		if info.FossilPath == "" {
+			fmt.Println("⚠️ Without fossil on the host, repositories can be stored there but not served or checked.")
+		}
+		if info.WebUser != "" && len(info.WritableDirs) > 0 {
+			printNextSteps(fmt.Sprintf("teryx transfer <repo.fossil> -d %s:%s -r %s", userHost, info.WritableDirs[0], info.WebUser))
+		}
+	},
+}
+
+func init() {
+	hostInfoCmd.Flags().Bool("json", false, "Print the findings as JSON")
+	hostInfoCmd.Flags().Duration("timeout", 10*time.Second, "How long to wait for the ssh connection")
+	hostInfoCmd.Flags().StringP("identity-file", "i", "", "SSH private key to use for ssh")
+	hostInfoCmd.Flags().String("bind-address", "", "Local IP address to connect from, for hosts with several interfaces")
+
+	rootCmd.AddCommand(hostInfoCmd)
+}
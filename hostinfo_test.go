@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHostInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   hostInfo
+	}{
+		{"full report",
+			"os=Linux\narch=x86_64\nrelease=Debian GNU/Linux 12 (bookworm)\nuser=deploy\n" +
+				"fossil=This is fossil version 2.23 [47362306a7] 2023-11-01 18:56:47 UTC\nfossil_path=/usr/bin/fossil\n" +
+				"web_process=www-data:nginx\naccount=www-data\naccount=fossil\nwritable=/srv/fossil\nreadonly=/var/www\n",
+			hostInfo{OS: "Linux", Arch: "x86_64", Release: "Debian GNU/Linux 12 (bookworm)", SSHUser: "deploy",
+				FossilVersion: "2.23", FossilPath: "/usr/bin/fossil", WebUser: "www-data",
+				WebServers: []string{"nginx (as www-data)"}, WebAccounts: []string{"www-data", "fossil"},
+				WritableDirs: []string{"/srv/fossil"}, ReadOnlyDirs: []string{"/var/www"}}},
+		{"running server wins over accounts",
+			"web_process=fossil:fossil\nweb_process=apache:httpd\naccount=apache\n",
+			hostInfo{WebUser: "fossil", WebServers: []string{"fossil (as fossil)", "httpd (as apache)"}, WebAccounts: []string{"apache"}}},
+		{"first account without a running server",
+			"account=nginx\naccount=www\n",
+			hostInfo{WebUser: "nginx", WebAccounts: []string{"nginx", "www"}}},
+		{"unrecognised fossil version is kept as is",
+			"fossil=fossil 3\n",
+			hostInfo{FossilVersion: "fossil 3"}},
+		{"empty values and unknown keys are ignored",
+			"os=Linux\nrelease=\nfossil=\nfossil_path=\ncolour=blue\nnot a finding\n",
+			hostInfo{OS: "Linux"}},
+		{"CRLF line endings",
+			"os=Linux\r\nuser=deploy\r\n",
+			hostInfo{OS: "Linux", SSHUser: "deploy"}},
+		{"no output", "", hostInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got hostInfo
+			parseHostInfo(&got, tt.output)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHostInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// importdir.go
+//
+// Implements 'teryx init --import-dir', which fills a new checkout with the
+// contents of an existing directory and commits them.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// copyTree copies the files, directories and symlinks under src into dst,
+// keeping file modes so executable bits reach the repository. The checkout
+// markers of any checkout inside src are left out, as are the paths in skip,
+// so a tree can be imported into a checkout nested within itself.
+func copyTree(src, dst string, skip ...string) error {
+	return filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(skip, absPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name := entry.Name(); name == ".fslckout" || name == "_FOSSIL_" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			if err := copyFile(path, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+		fmt.Printf("⏭️  Skipping %s, which is not a regular file.\n", path)
+		return nil
+	})
+}
+
+// importIntoCheckout copies importDir into the checkout at checkoutDir, adds
+// the files and commits them with message. 'fossil add' applies the ignore-glob
+// of a .fossil-settings directory copied along, and skips dotfiles as usual.
+// The commit doesn't sync, since the remote of a new repository rarely exists
+// yet. Without commit, the files are only added and left pending. It reports
+// how many files were committed or added.
+func importIntoCheckout(importDir, checkoutDir, message string, commit bool, skip ...string) (int, error) {
+	// Compare real paths, so symlinked directories don't defeat skip.
+	if resolved, err := filepath.EvalSymlinks(importDir); err == nil {
+		importDir = resolved
+	}
+	for i, path := range skip {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			skip[i] = resolved
+		}
+	}
+	if err := copyTree(importDir, checkoutDir, skip...); err != nil {
+		return 0, fmt.Errorf("failed to copy '%s' into the checkout: %w", importDir, err)
+	}
+
+	addArgs := []string{"add", "."}
+	if _, err := os.Stat(filepath.Join(checkoutDir, ".fossil-settings")); err == nil {
+		addArgs = append(addArgs, ".fossil-settings")
+	}
+	if err := executeCommand(checkoutDir, "fossil", addArgs...); err != nil {
+		return 0, fmt.Errorf("failed to add the imported files: %w", err)
+	}
+
+	changes, err := pendingChanges(checkoutDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the imported files: %w", err)
+	}
+	if len(changes) == 0 || !commit {
+		return len(changes), nil
+	}
+	if err := executeCommand(checkoutDir, "fossil", "commit", "-m", message, "--nosync"); err != nil {
+		return 0, fmt.Errorf("failed to commit the imported files: %w", err)
+	}
+	return len(changes), nil
+}
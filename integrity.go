@@ -0,0 +1,78 @@
+// integrity.go
+//
+// Implements 'teryx test-integrity', a scriptable wrapper around
+// 'fossil test-integrity' that exits non-zero when problems are reported.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// integrityErrorsPattern matches the error tally fossil prints at the end of
+// 'fossil test-integrity', e.g. "checked:  0 errors".
+var integrityErrorsPattern = regexp.MustCompile(`(\d+) errors`)
+
+// testIntegrity runs 'fossil test-integrity' with the given repository
+// arguments and returns an error if fossil fails or reports any errors.
+// Output is streamed to the terminal as well as inspected.
+func testIntegrity(repoArgs []string, quick bool) error {
+	fossilArgs := append([]string{"test-integrity"}, repoArgs...)
+	if quick {
+		fossilArgs = append(fossilArgs, "--quick")
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command("fossil", fossilArgs...)
+	cmd.Stdout = io.MultiWriter(os.Stdout, &output)
+	cmd.Stderr = os.Stderr
+
+	printBanner(os.Stdout, cmd)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("fossil test-integrity failed: %s", err)
+	}
+
+	for _, match := range integrityErrorsPattern.FindAllStringSubmatch(output.String(), -1) {
+		if n, _ := strconv.Atoi(match[1]); n > 0 {
+			return fmt.Errorf("integrity check reported %d errors", n)
+		}
+	}
+	return nil
+}
+
+// testIntegrityCmd handles the 'teryx test-integrity' command.
+var testIntegrityCmd = &cobra.Command{
+	Use:   "test-integrity [repository-file]",
+	Short: "Checks a repository for corruption, exiting non-zero on any problem.",
+	Long: `Wraps 'fossil test-integrity' to verify the repository of the open checkout,
+or the repository file given as an argument or via -R. The exit status is
+non-zero if fossil reports any errors, so the command can be used in scripts.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		quick, _ := cmd.Flags().GetBool("quick")
+
+		fmt.Println("🚀 Checking repository integrity...")
+		if err := testIntegrity(repositoryArgs(cmd, args), quick); err != nil {
+			log.Fatalf("❌ Integrity check failed: %v", err)
+		}
+
+		fmt.Println("✅ Success! No integrity problems found.")
+	},
+}
+
+func init() {
+	addRepositoryFlag(testIntegrityCmd)
+	testIntegrityCmd.Flags().Bool("quick", false, "Run fossil's faster, less thorough check")
+
+	rootCmd.AddCommand(testIntegrityCmd)
+}
@@ -0,0 +1,118 @@
+// locate.go
+//
+// Implements 'teryx locate', which finds local clones under the fossils base
+// directory by name.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// locatedRepo is one match of 'teryx locate'. Checkout is empty when no
+// checkout is open next to the repository file.
+type locatedRepo struct {
+	Repo     string `json:"repo"`
+	Checkout string `json:"checkout,omitempty"`
+	rank     int
+}
+
+// matchRank scores how well query matches a repository, given its name
+// (without .fossil) and its path relative to the base directory: 0 for the
+// exact name, 1 for a name prefix, 2 for a substring of the name, 3 for a
+// substring of the path, and 4 for a fuzzy match where the query's characters
+// appear in order in the path. It returns -1 for no match. Case is ignored.
+func matchRank(query, name, relPath string) int {
+	query, name, relPath = strings.ToLower(query), strings.ToLower(name), strings.ToLower(relPath)
+	switch {
+	case name == query:
+		return 0
+	case strings.HasPrefix(name, query):
+		return 1
+	case strings.Contains(name, query):
+		return 2
+	case strings.Contains(relPath, query):
+		return 3
+	}
+	rest := relPath
+	for _, r := range query {
+		i := strings.IndexRune(rest, r)
+		if i < 0 {
+			return -1
+		}
+		rest = rest[i+len(string(r)):]
+	}
+	return 4
+}
+
+// locateCmd handles the 'teryx locate' command.
+var locateCmd = &cobra.Command{
+	Use:   "locate <name>",
+	Short: "Finds local clones under the fossils directory by name.",
+	Long: `Searches the fossils base directory ($HOME/fossils, or base-dir from the config
+file) for repository files matching the name and prints their paths, with the
+checkout next to each, if any. Matching ignores case and is fuzzy: exact names
+come first, then names starting with or containing the text, then paths
+containing it, then paths containing its characters in order (so 'tpj' finds
+team/project). Every match is listed; --json prints them as an array.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := args[0]
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		baseDir, err := fossilsBaseDir()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		repos, err := findRepoFiles(baseDir)
+		if err != nil {
+			log.Fatalf("❌ Failed to search %s: %v", baseDir, err)
+		}
+
+		matches := []locatedRepo{}
+		for _, repoPath := range repos {
+			relPath, err := filepath.Rel(baseDir, repoPath)
+			if err != nil {
+				relPath = repoPath
+			}
+			rank := matchRank(query, strings.TrimSuffix(filepath.Base(repoPath), ".fossil"), relPath)
+			if rank < 0 {
+				continue
+			}
+			match := locatedRepo{Repo: repoPath, rank: rank}
+			if checkoutDir := standardCheckoutDir(repoPath); isCheckoutDir(checkoutDir) {
+				match.Checkout = checkoutDir
+			}
+			matches = append(matches, match)
+		}
+		slices.SortStableFunc(matches, func(a, b locatedRepo) int { return a.rank - b.rank })
+
+		if asJSON {
+			if err := printJSON(matches); err != nil {
+				log.Fatalf("❌ Failed to write JSON: %v", err)
+			}
+			return
+		}
+		if len(matches) == 0 {
+			log.Fatalf("❌ No repository matching '%s' under %s.", query, baseDir)
+		}
+		for _, match := range matches {
+			fmt.Println(match.Repo)
+			if match.Checkout != "" {
+				fmt.Printf("    checkout: %s\n", match.Checkout)
+			}
+		}
+	},
+}
+
+func init() {
+	locateCmd.Flags().Bool("json", false, "Print the matches as a JSON array of {\"repo\", \"checkout\"} objects")
+
+	rootCmd.AddCommand(locateCmd)
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestMatchRank(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		repoName string
+		relPath  string
+		want     int
+	}{
+		{"exact name", "project", "project", "team/project.fossil", 0},
+		{"exact name ignores case", "Project", "PROJECT", "team/PROJECT.fossil", 0},
+		{"name prefix", "proj", "project", "team/project.fossil", 1},
+		{"name substring", "ject", "project", "team/project.fossil", 2},
+		{"path substring", "team", "project", "team/project.fossil", 3},
+		{"characters in order", "tpj", "project", "team/project.fossil", 4},
+		{"characters out of order", "jpt", "project", "team/project.fossil", -1},
+		{"repeated character needs two", "pp", "project", "team/project.fossil", -1},
+		{"no match", "zzz", "project", "team/project.fossil", -1},
+		{"non-ASCII characters in order", "éo", "café-notes", "misc/café-notes.fossil", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchRank(tt.query, tt.repoName, tt.relPath); got != tt.want {
+				t.Errorf("matchRank(%q, %q, %q) = %d, want %d", tt.query, tt.repoName, tt.relPath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// log.go
+//
+// Implements 'teryx log', which prints the check-in history in the style of
+// 'git log'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// formatLogEntry expands the git-style placeholders in format for entry:
+// %H (full hash), %h (short hash), %an (author), %ad (date), %d (branch),
+// %s (comment), %n (newline) and %% (a literal percent sign).
+func formatLogEntry(format string, entry timelineEntry) string {
+	return strings.NewReplacer(
+		"%%", "%",
+		"%H", entry.Hash,
+		"%h", entry.Hash[:min(10, len(entry.Hash))],
+		"%an", entry.Author,
+		"%ad", entry.Date,
+		"%d", entry.Branch,
+		"%s", entry.Comment,
+		"%n", "\n",
+	).Replace(format)
+}
+
+// logCmd handles the 'teryx log' command.
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Shows the check-in history in a git-log-like format.",
+	Long: `Reads the check-ins of the open checkout, or the repository given with -R, from
+'fossil timeline' and prints them like 'git log' does. --oneline prints a short
+hash and the comment per line, and --format takes a template with the
+placeholders %H (hash), %h (short hash), %an (author), %ad (date), %d (branch),
+%s (comment) and %n (newline).`,
+	Args:    cobra.NoArgs,
+	PreRunE: rejectFlagConflicts(checkoutOrRepositoryPreRun, [2]string{"oneline", "format"}),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		oneline, _ := cmd.Flags().GetBool("oneline")
+		format, _ := cmd.Flags().GetString("format")
+
+		timelineArgs := append([]string{"-n", strconv.Itoa(limit)}, repositoryArgs(cmd, nil)...)
+		entries, err := fetchTimeline(timelineArgs...)
+		if err != nil {
+			log.Fatalf("❌ Failed to read timeline: %v", err)
+		}
+
+		if oneline {
+			format = "%h %s"
+		}
+		for i, entry := range entries {
+			if format != "" {
+				fmt.Println(formatLogEntry(format, entry))
+				continue
+			}
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("commit %s (%s)\n", entry.Hash, entry.Branch)
+			fmt.Printf("Author: %s\n", entry.Author)
+			fmt.Printf("Date:   %s\n\n", entry.Date)
+			fmt.Printf("    %s\n", entry.Comment)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(logCmd)
+	logCmd.Flags().IntP("limit", "n", 20, "Maximum number of check-ins to show (0 for no limit)")
+	logCmd.Flags().Bool("oneline", false, "Show each check-in as a short hash and its comment")
+	logCmd.Flags().String("format", "", "Print each check-in with this template (e.g. \"%h %an %s\")")
+
+	rootCmd.AddCommand(logCmd)
+}
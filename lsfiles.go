@@ -0,0 +1,89 @@
+// lsfiles.go
+//
+// Implements 'teryx ls-files', which lists the files fossil tracks at a
+// revision, e.g. for build tooling that enumerates sources.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// lsFilesCmd handles the 'teryx ls-files' command.
+var lsFilesCmd = &cobra.Command{
+	Use:   "ls-files",
+	Short: "Lists the files tracked at a revision.",
+	Long: `Wraps 'fossil ls' to list the files tracked in the open checkout, or, with
+--rev, in another check-in, one path per line relative to the checkout root.
+With -R, the files of a repository file are listed; without a checkout, --rev
+defaults to the tip.
+
+--full-path prints absolute paths inside the open checkout instead, and --json
+prints a JSON array of paths.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		rev, _ := cmd.Flags().GetString("rev")
+		fullPath, _ := cmd.Flags().GetBool("full-path")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		repoArgs := repositoryArgs(cmd, args)
+
+		if repoArgs != nil && rev == "" {
+			rev = "tip"
+		}
+		root := ""
+		if fullPath {
+			if repoArgs != nil {
+				log.Fatal("❌ --full-path needs an open checkout; files of a repository file have no location on disk.")
+			}
+			var err error
+			if root, err = fossilInfoField("", "local-root"); err != nil {
+				log.Fatalf("❌ Could not determine the checkout root: %v", err)
+			}
+		}
+
+		fossilArgs := []string{"ls"}
+		if rev != "" {
+			fossilArgs = append(fossilArgs, "-r", rev)
+		}
+		output, err := captureCommand("", "fossil", append(append([]string{}, fossilArgs...), repoArgs...)...)
+		if err != nil {
+			log.Fatalf("❌ Failed to list files: %v", err)
+		}
+
+		files := []string{}
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			if root != "" {
+				line = filepath.Join(root, line)
+			}
+			files = append(files, line)
+		}
+
+		if asJSON {
+			if err := printJSON(files); err != nil {
+				log.Fatalf("❌ Failed to encode files: %v", err)
+			}
+			return
+		}
+		for _, file := range files {
+			fmt.Println(file)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(lsFilesCmd)
+	lsFilesCmd.Flags().String("rev", "", "Check-in to list the files of (default: the checked-out revision)")
+	lsFilesCmd.Flags().Bool("full-path", false, "Print absolute paths inside the open checkout")
+	lsFilesCmd.Flags().Bool("json", false, "Print the paths as a JSON array")
+
+	rootCmd.AddCommand(lsFilesCmd)
+}
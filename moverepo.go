@@ -0,0 +1,153 @@
+// moverepo.go
+//
+// Implements 'teryx move-repo', which migrates a repository file to a server:
+// it transfers and verifies the file like 'teryx transfer --verify', and only
+// then deletes the local copy.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// checkpointWAL makes sure the repository file at repoPath holds all of its
+// changes before it is copied. A repository in WAL mode (see 'init --wal') can
+// keep recent changes in <name>.fossil-wal; those are written back with a
+// checkpoint, and if the -wal file still isn't empty afterwards, for example
+// because a server is using the repository, the move is refused.
+func checkpointWAL(repoPath string) error {
+	walPath := repoPath + "-wal"
+	if info, err := os.Stat(walPath); err != nil || info.Size() == 0 {
+		return nil
+	}
+	fmt.Printf("🚀 Writing the changes in '%s' back into the repository...\n", walPath)
+	if _, err := captureCommand("", "fossil", "sql", "-R", repoPath, "PRAGMA wal_checkpoint(TRUNCATE);"); err != nil {
+		return fmt.Errorf("could not checkpoint '%s': %v", walPath, err)
+	}
+	if info, err := os.Stat(walPath); err == nil && info.Size() > 0 {
+		return fmt.Errorf("'%s' still holds changes not in the repository file; stop whatever is using it and try again", walPath)
+	}
+	return nil
+}
+
+// moveRepoCmd handles the 'teryx move-repo' command.
+var moveRepoCmd = &cobra.Command{
+	Use:   "move-repo <repository-file> <user@host:path>",
+	Short: "Transfers a repository to a server, verifies it and deletes the local copy.",
+	Long: `Moves a repository file to a server instead of copying it: the local file is
+checked with 'fossil test-integrity', transferred with scp (or sftp), and its
+remote copy compared by checksum, as with 'teryx transfer --verify'. Only when
+all of that succeeds is the local repository file deleted; if any step fails,
+nothing local is touched.
+
+--with-checkout also closes and deletes the checkout 'teryx init' or
+'teryx clone' opened next to the file (the directory of the same name). A
+checkout with uncommitted changes makes the move fail before anything is
+transferred. Without --with-checkout, any checkout there makes it fail too,
+since it would be left pointing at a deleted repository.
+
+A repository in WAL mode (see 'teryx init --wal') is checkpointed first, so
+changes still in its -wal file are moved too; if they can't be written back,
+nothing is moved.
+
+Because the local copy is deleted, --yes is required to confirm the move.
+The destination accepts the same {date}, {reponame} and {host} placeholders
+as 'teryx transfer'.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		repoPath, destination := args[0], args[1]
+		yes, _ := cmd.Flags().GetBool("yes")
+		withCheckout, _ := cmd.Flags().GetBool("with-checkout")
+		hashAlgo, _ := cmd.Flags().GetString("hash-algo")
+
+		if !yes {
+			log.Fatal("❌ move-repo deletes the local repository after the transfer; pass --yes to confirm.")
+		}
+		info, err := os.Stat(repoPath)
+		if err != nil {
+			log.Fatalf("❌ Cannot read repository file: %v", err)
+		}
+		if info.IsDir() {
+			log.Fatalf("❌ '%s' is a directory; move-repo moves one repository file.", repoPath)
+		}
+		userHost, remotePath, err := splitDestination(destination)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if remotePath, err = expandDestination(remotePath, repoPath); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		sshOpts, err := transferSSHOptions(cmd)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		clone := staleClone{RepoPath: repoPath}
+		checkoutDir := standardCheckoutDir(repoPath)
+		hasCheckout := isCheckoutDir(checkoutDir)
+		if hasCheckout && !withCheckout {
+			log.Fatalf("❌ '%s' is a checkout of this repository and would be left pointing at a deleted file;\n"+
+				"   pass --with-checkout to remove it as well.", checkoutDir)
+		}
+		if withCheckout {
+			if !hasCheckout {
+				log.Fatalf("❌ There is no checkout at '%s' to remove with --with-checkout.", checkoutDir)
+			}
+			dirty, err := hasUncommittedChanges(checkoutDir)
+			if err != nil {
+				log.Fatalf("❌ Could not check %s for uncommitted changes: %v", checkoutDir, err)
+			}
+			if dirty {
+				log.Fatalf("❌ '%s' has uncommitted changes; commit them before moving the repository.", checkoutDir)
+			}
+			clone.CheckoutDir = checkoutDir
+		}
+
+		if err := checkpointWAL(repoPath); err != nil {
+			log.Fatalf("❌ %v; nothing was transferred.", err)
+		}
+		fmt.Printf("🚀 Checking the integrity of '%s'...\n", repoPath)
+		if err := testIntegrity([]string{"-R", repoPath}, false); err != nil {
+			log.Fatalf("❌ Integrity check failed, so nothing was transferred: %v", err)
+		}
+		hashTool, err := findRemoteHashTool(sshOpts, userHost, hashAlgo)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		fileDestination := userHost + ":" + remotePath
+		fmt.Printf("🚀 Attempting to transfer '%s' to '%s' via scp...\n", repoPath, fileDestination)
+		if err := transferFile(repoPath, fileDestination, userHost, remotePath, sshOpts, false); err != nil {
+			log.Fatalf("❌ %v; the local copy was kept.", err)
+		}
+		finalPath := remoteRepoPath(remotePath, repoPath)
+		if err := verifyTransfer(sshOpts, userHost, hashTool, repoPath, finalPath); err != nil {
+			log.Fatalf("❌ Verification failed, so the local copy was kept: %v", err)
+		}
+		fmt.Printf("🔒 Verified %s:%s with %s.\n", userHost, finalPath, hashTool.Name)
+
+		if err := removeClone(clone); err != nil {
+			log.Fatalf("❌ The repository was moved, but removing the local copy failed: %v", err)
+		}
+		if clone.CheckoutDir != "" {
+			fmt.Printf("🗑️  Removed %s\n", clone.CheckoutDir)
+		}
+		fmt.Printf("🗑️  Removed %s\n", repoPath)
+		fmt.Printf("✅ Success! Repository moved to %s:%s.\n", userHost, finalPath)
+		printNextSteps(fmt.Sprintf("teryx reconcile-perms %s %s", userHost, finalPath))
+	},
+}
+
+func init() {
+	moveRepoCmd.Flags().Bool("yes", false, "Confirm that the local repository is deleted once the transfer is verified")
+	moveRepoCmd.Flags().Bool("with-checkout", false, "Also close and delete the checkout next to the repository file")
+	moveRepoCmd.Flags().String("hash-algo", "", "Checksum tool to verify with: sha256sum, shasum, md5sum or cksum (default: the best one on the remote host)")
+	moveRepoCmd.Flags().StringP("identity-file", "i", "", "SSH private key to use for scp/sftp")
+	moveRepoCmd.Flags().String("bind-address", "", "Local IP address to connect from, for hosts with several interfaces")
+
+	rootCmd.AddCommand(moveRepoCmd)
+}
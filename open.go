@@ -0,0 +1,85 @@
+// open.go
+//
+// Implements 'teryx open', which opens a checkout of a repository file in a
+// directory, explaining rather than failing when that would nest checkouts.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// openCheckoutIn opens a checkout of repoPath in the existing directory dir.
+// It refuses a directory that already is a checkout and, unless nested is set,
+// one inside another checkout, explaining why instead of leaving it to fossil.
+func openCheckoutIn(dir, repoPath string, nested bool) error {
+	if isCheckoutDir(dir) {
+		return fmt.Errorf("'%s' already is a checkout", dir)
+	}
+	if requireCheckout(dir) == nil {
+		if !nested {
+			return fmt.Errorf("'%s' is inside another Fossil checkout, and fossil won't open a checkout within one.\n"+
+				"   The outer checkout would see every file of the new one as an extra. Open it somewhere\n"+
+				"   else, or pass --nested if this really is what you want", dir)
+		}
+		fmt.Println("⚠️ Opening a nested checkout. The outer checkout will report its files as extras;")
+		fmt.Println("⚠️ add the directory to the outer repository's ignore-glob to hide them.")
+	}
+
+	fossilArgs := []string{"open", repoPath}
+	if nested {
+		fossilArgs = append(fossilArgs, "--nested")
+	}
+	if err := executeCommand(dir, "fossil", fossilArgs...); err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+	return nil
+}
+
+// openCmd handles the 'teryx open' command.
+var openCmd = &cobra.Command{
+	Use:   "open <repository-file> [directory]",
+	Short: "Opens a checkout of a repository in a directory.",
+	Long: `Opens a checkout of the repository file in the given directory, which is
+created if needed, or in the current directory.
+
+Fossil refuses to open a checkout inside another one. teryx detects this up
+front and explains it. Pass --nested to do it anyway.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		nested, _ := cmd.Flags().GetBool("nested")
+		dir := "."
+		if len(args) > 1 {
+			dir = args[1]
+		}
+
+		repoPath, err := filepath.Abs(args[0])
+		if err != nil {
+			log.Fatalf("❌ Invalid repository path: %v", err)
+		}
+		if _, err := os.Stat(repoPath); err != nil {
+			log.Fatalf("❌ Cannot read repository file: %v", err)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Fatalf("❌ Failed to create directory: %v", err)
+		}
+
+		if err := openCheckoutIn(dir, repoPath, nested); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		absDir, _ := filepath.Abs(dir)
+		fmt.Printf("✅ Success! Repo opened in: %s\n", absDir)
+	},
+}
+
+func init() {
+	openCmd.Flags().Bool("nested", false, "Allow opening the checkout inside another checkout")
+
+	rootCmd.AddCommand(openCmd)
+}
@@ -0,0 +1,81 @@
+// prompt.go
+//
+// Implements 'teryx prompt' and 'teryx shell-init', which show the current
+// fossil branch in the shell prompt.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// promptShellSnippets are the shell functions printed by 'teryx shell-init'.
+var promptShellSnippets = map[string]string{
+	"bash": `__teryx_ps1() { teryx prompt --format "${1:- (%s)}"; }
+# Add $(__teryx_ps1) to PS1, e.g.:
+#   PS1='\u@\h:\w$(__teryx_ps1)\$ '
+`,
+	"zsh": `__teryx_ps1() { teryx prompt --format "${1:- (%s)}"; }
+setopt PROMPT_SUBST
+# Add $(__teryx_ps1) to PROMPT, e.g.:
+#   PROMPT='%n@%m:%~$(__teryx_ps1)%# '
+`,
+}
+
+// promptCmd handles the 'teryx prompt' command.
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Prints the current branch for use in a shell prompt.",
+	Long: `Prints the branch of the checkout containing the working directory, formatted
+with --format, and nothing else. Outside a checkout, or on any error, it prints
+nothing and exits successfully, so it is safe to call from PS1. The checkout is
+found without running fossil, so the common case outside a checkout is instant.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		if requireCheckout("") != nil {
+			return
+		}
+		// Run fossil directly rather than via captureCommand, whose banner
+		// would end up in the prompt.
+		output, err := exec.Command("fossil", "branch", "current").Output()
+		if err != nil {
+			return
+		}
+		if branch := strings.TrimSpace(string(output)); branch != "" {
+			fmt.Print(strings.ReplaceAll(format, "%s", branch))
+		}
+	},
+}
+
+// shellInitCmd handles the 'teryx shell-init' command.
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh>",
+	Short: "Prints a shell snippet that shows the fossil branch in the prompt.",
+	Long: `Prints a __teryx_ps1 shell function, built on 'teryx prompt', and an example of
+adding it to the prompt. Add it to your shell's startup file, e.g.:
+
+  eval "$(teryx shell-init bash)"`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh"},
+	Run: func(cmd *cobra.Command, args []string) {
+		snippet, ok := promptShellSnippets[args[0]]
+		if !ok {
+			log.Fatalf("❌ Unsupported shell '%s'. Supported shells: bash, zsh", args[0])
+		}
+		fmt.Print(snippet)
+	},
+}
+
+func init() {
+	promptCmd.Flags().String("format", "%s", "Output template; %s is replaced with the branch (e.g. \" (%s)\")")
+
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(shellInitCmd)
+}
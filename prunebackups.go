@@ -0,0 +1,173 @@
+// prunebackups.go
+//
+// Implements 'teryx prune-backups', which applies a retention policy to a
+// directory of timestamped repository backups, e.g. from cron.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// backupNamePattern matches backup file names of the form
+// <repo>-<timestamp>.fossil[.gz], where the timestamp is either
+// YYYYMMDD-HHMMSS or the YYYY-MM-DD of transfer's {date} placeholder.
+var backupNamePattern = regexp.MustCompile(`^(.+)-(\d{8}-\d{6}|\d{4}-\d{2}-\d{2})\.fossil(\.gz)?$`)
+
+// backupFile is one backup found by parseBackupName.
+type backupFile struct {
+	Path string
+	Repo string
+	Time time.Time
+}
+
+// parseBackupName returns the backup described by a file name, and false for
+// files that don't follow the naming convention.
+func parseBackupName(path string) (backupFile, bool) {
+	match := backupNamePattern.FindStringSubmatch(filepath.Base(path))
+	if match == nil {
+		return backupFile{}, false
+	}
+	layout := "20060102-150405"
+	if len(match[2]) == len("2006-01-02") {
+		layout = "2006-01-02"
+	}
+	stamp, err := time.ParseInLocation(layout, match[2], time.Local)
+	if err != nil {
+		return backupFile{}, false
+	}
+	return backupFile{Path: path, Repo: match[1], Time: stamp}, true
+}
+
+// backupsToPrune returns the backups that fall outside the retention policy.
+// For each repository, the newest keep backups are kept, plus the newest
+// backup of each of the last daily days and weekly weeks that have one.
+func backupsToPrune(backups []backupFile, keep, daily, weekly int) []backupFile {
+	byRepo := make(map[string][]backupFile)
+	for _, backup := range backups {
+		byRepo[backup.Repo] = append(byRepo[backup.Repo], backup)
+	}
+
+	var prune []backupFile
+	for _, repoBackups := range byRepo {
+		slices.SortFunc(repoBackups, func(a, b backupFile) int { return b.Time.Compare(a.Time) })
+		kept := make(map[string]bool)
+		for i := range min(keep, len(repoBackups)) {
+			kept[repoBackups[i].Path] = true
+		}
+		// Newest first, so the first backup seen in a period is the one kept.
+		keepPeriods := func(limit int, period func(time.Time) string) {
+			seen := make(map[string]bool)
+			for _, backup := range repoBackups {
+				key := period(backup.Time)
+				if !seen[key] && len(seen) < limit {
+					seen[key] = true
+					kept[backup.Path] = true
+				}
+			}
+		}
+		keepPeriods(daily, func(t time.Time) string { return t.Format("2006-01-02") })
+		keepPeriods(weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		})
+
+		for _, backup := range repoBackups {
+			if !kept[backup.Path] {
+				prune = append(prune, backup)
+			}
+		}
+	}
+	slices.SortFunc(prune, func(a, b backupFile) int { return strings.Compare(a.Path, b.Path) })
+	return prune
+}
+
+// pruneBackupsCmd handles the 'teryx prune-backups' command.
+var pruneBackupsCmd = &cobra.Command{
+	Use:   "prune-backups <directory>",
+	Short: "Deletes old repository backups according to a retention policy.",
+	Long: `Applies a retention policy to the backups in a directory, whose names must have
+the form <repo>-<YYYYMMDD-HHMMSS>.fossil or <repo>-<YYYY-MM-DD>.fossil (as
+written by 'teryx transfer -d host:/backups/{reponame}-{date}.fossil'),
+optionally gzip-compressed (.fossil.gz). Other files are left alone.
+
+For each repository, the newest --keep backups are kept, plus the newest
+backup of each of the last --keep-daily days and --keep-weekly weeks that have
+one. Everything else is deleted without asking, so the command suits cron; use
+--dry-run first to see what would go.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keep, _ := cmd.Flags().GetInt("keep")
+		daily, _ := cmd.Flags().GetInt("keep-daily")
+		weekly, _ := cmd.Flags().GetInt("keep-weekly")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		if keep < 0 || daily < 0 || weekly < 0 {
+			log.Fatal("❌ --keep, --keep-daily and --keep-weekly can't be negative.")
+		}
+		if keep == 0 && daily == 0 && weekly == 0 {
+			log.Fatal("❌ The policy would delete every backup; set --keep, --keep-daily or --keep-weekly.")
+		}
+
+		entries, err := os.ReadDir(args[0])
+		if err != nil {
+			log.Fatalf("❌ Cannot read directory: %v", err)
+		}
+		var backups []backupFile
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if backup, ok := parseBackupName(filepath.Join(args[0], entry.Name())); ok {
+				backups = append(backups, backup)
+			}
+		}
+
+		prune := backupsToPrune(backups, keep, daily, weekly)
+		if len(prune) == 0 {
+			fmt.Printf("✅ Nothing to prune; all %d backups are kept.\n", len(backups))
+			return
+		}
+
+		failed := 0
+		for _, backup := range prune {
+			if dryRun {
+				fmt.Printf("ℹ️  Would delete %s\n", backup.Path)
+				continue
+			}
+			if err := os.Remove(backup.Path); err != nil {
+				fmt.Printf("⚠️ Failed to delete %s: %v\n", backup.Path, err)
+				failed++
+				continue
+			}
+			fmt.Printf("🗑️  Deleted %s\n", backup.Path)
+		}
+
+		if dryRun {
+			fmt.Printf("ℹ️  Dry run: %d of %d backups would be deleted.\n", len(prune), len(backups))
+			return
+		}
+		if failed > 0 {
+			log.Fatalf("❌ %d backups could not be deleted.", failed)
+		}
+		fmt.Printf("✅ Success! Deleted %d of %d backups.\n", len(prune), len(backups))
+	},
+}
+
+func init() {
+	pruneBackupsCmd.Flags().Int("keep", 7, "Number of most recent backups to keep per repository")
+	pruneBackupsCmd.Flags().Int("keep-daily", 0, "Also keep the newest backup of each of this many recent days")
+	pruneBackupsCmd.Flags().Int("keep-weekly", 0, "Also keep the newest backup of each of this many recent weeks")
+	pruneBackupsCmd.Flags().Bool("dry-run", false, "List the backups that would be deleted without deleting them")
+
+	rootCmd.AddCommand(pruneBackupsCmd)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBackupName(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   backupFile
+		wantOK bool
+	}{
+		{"/backups/project-20240102-030405.fossil",
+			backupFile{"/backups/project-20240102-030405.fossil", "project", time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)}, true},
+		{"my-repo-2024-01-02.fossil",
+			backupFile{"my-repo-2024-01-02.fossil", "my-repo", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)}, true},
+		{"project-2024-01-02.fossil.gz",
+			backupFile{"project-2024-01-02.fossil.gz", "project", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)}, true},
+		{"project.fossil", backupFile{}, false},
+		{"project-2024-01-02.tar.gz", backupFile{}, false},
+		{"project-2024-13-02.fossil", backupFile{}, false},
+		{"-2024-01-02.fossil", backupFile{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := parseBackupName(tt.path)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBackupName(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackupsToPrune(t *testing.T) {
+	// Daily backups of "a" from Monday 2024-01-01 to Sunday 2024-01-14,
+	// that is ISO weeks 1 and 2, and one backup of "b".
+	var backups []backupFile
+	for day := 1; day <= 14; day++ {
+		backups = append(backups, backupFile{
+			Path: fmt.Sprintf("a-2024-01-%02d.fossil", day),
+			Repo: "a",
+			Time: time.Date(2024, 1, day, 0, 0, 0, 0, time.Local),
+		})
+	}
+	backups = append(backups, backupFile{Path: "b-2024-01-01.fossil", Repo: "b", Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)})
+
+	paths := func(days ...int) []string {
+		var result []string
+		for _, day := range days {
+			result = append(result, fmt.Sprintf("a-2024-01-%02d.fossil", day))
+		}
+		return result
+	}
+	tests := []struct {
+		name                string
+		keep, daily, weekly int
+		want                []string
+	}{
+		{"newest only", 1, 0, 0, paths(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)},
+		{"keep overlaps daily", 2, 3, 0, paths(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)},
+		{"weekly keeps the newest of each week", 0, 0, 2, paths(1, 2, 3, 4, 5, 6, 8, 9, 10, 11, 12, 13)},
+		{"all rules together", 2, 3, 2, paths(1, 2, 3, 4, 5, 6, 8, 9, 10, 11)},
+		{"keep more than there are", 20, 0, 0, nil},
+		{"keep nothing", 0, 0, 0, append(paths(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14), "b-2024-01-01.fossil")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, backup := range backupsToPrune(backups, tt.keep, tt.daily, tt.weekly) {
+				got = append(got, backup.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("backupsToPrune(keep %d, daily %d, weekly %d) = %v, want %v", tt.keep, tt.daily, tt.weekly, got, tt.want)
+			}
+		})
+	}
+}
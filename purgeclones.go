@@ -0,0 +1,143 @@
+// purgeclones.go
+//
+// Implements 'teryx purge-clones', which finds clones under the fossils base
+// directory that haven't been touched for a while and optionally deletes them.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// staleClone is a repository file, and the checkout next to it if there is
+// one, that has not been modified within the --older-than age.
+type staleClone struct {
+	RepoPath    string
+	CheckoutDir string
+	Size        int64
+	ModTime     time.Time
+}
+
+// findStaleClones returns the clones under baseDir whose repository file was
+// last modified before cutoff. Fossil writes to the repository on every sync,
+// so the modification time approximates the last sync.
+func findStaleClones(baseDir string, cutoff time.Time) ([]staleClone, error) {
+	repos, err := findRepoFiles(baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var stale []staleClone
+	for _, repoPath := range repos {
+		info, err := os.Stat(repoPath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.ModTime().Before(cutoff) {
+			continue
+		}
+		clone := staleClone{RepoPath: repoPath, Size: info.Size(), ModTime: info.ModTime()}
+		if checkoutDir := standardCheckoutDir(repoPath); isCheckoutDir(checkoutDir) {
+			clone.CheckoutDir = checkoutDir
+		}
+		stale = append(stale, clone)
+	}
+	return stale, nil
+}
+
+// removeClone closes and deletes a clone's checkout, if any, and then its
+// repository file. A checkout with uncommitted changes is left alone.
+func removeClone(clone staleClone) error {
+	if clone.CheckoutDir != "" {
+		dirty, err := hasUncommittedChanges(clone.CheckoutDir)
+		if err != nil {
+			return fmt.Errorf("could not check %s for uncommitted changes: %w", clone.CheckoutDir, err)
+		}
+		if dirty {
+			return fmt.Errorf("%s has uncommitted changes; use 'teryx rm-checkout --force --remove-repo' to remove it anyway", clone.CheckoutDir)
+		}
+		if err := executeCommand(clone.CheckoutDir, "fossil", "close"); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(clone.CheckoutDir); err != nil {
+			return err
+		}
+	}
+	return os.Remove(clone.RepoPath)
+}
+
+// purgeClonesCmd handles the 'teryx purge-clones' command.
+var purgeClonesCmd = &cobra.Command{
+	Use:   "purge-clones",
+	Short: "Lists, and with --yes deletes, clones that haven't synced in a while.",
+	Long: `Walks the fossils base directory ($HOME/fossils, or base-dir from the config
+file) for repository files that haven't been modified, and so not synced, for
+longer than --older-than (e.g. 12w or 90d), and lists them with their sizes.
+
+Nothing is deleted unless --yes is given; even then you are asked to confirm.
+Each clone's checkout is closed and deleted along with the repository file.
+Checkouts with uncommitted changes are skipped.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, _ := cmd.Flags().GetString("older-than")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		age, err := parseAge(olderThan)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		baseDir, err := fossilsBaseDir()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		stale, err := findStaleClones(baseDir, time.Now().Add(-age))
+		if err != nil {
+			log.Fatalf("❌ Failed to scan %s: %v", baseDir, err)
+		}
+		if len(stale) == 0 {
+			fmt.Printf("ℹ️  No clones under %s older than %s.\n", baseDir, olderThan)
+			return
+		}
+
+		var total int64
+		for _, clone := range stale {
+			fmt.Printf("%s  %10s  %s\n", clone.ModTime.Format("2006-01-02"), formatBytes(clone.Size), clone.RepoPath)
+			total += clone.Size
+		}
+		fmt.Printf("ℹ️  %d clones, %s in total.\n", len(stale), formatBytes(total))
+
+		if !yes {
+			fmt.Println("ℹ️  Dry run; nothing was removed. Use --yes to delete these clones.")
+			return
+		}
+		if !confirm(fmt.Sprintf("Permanently delete these %d clones and their checkouts?", len(stale))) {
+			fmt.Println("ℹ️  Aborted; nothing was removed.")
+			return
+		}
+
+		removed := 0
+		for _, clone := range stale {
+			if err := removeClone(clone); err != nil {
+				fmt.Printf("⚠️ Skipped %s: %v\n", clone.RepoPath, err)
+				continue
+			}
+			fmt.Printf("🗑️  Removed %s\n", clone.RepoPath)
+			removed++
+		}
+
+		fmt.Printf("✅ Success! Removed %d of %d clones.\n", removed, len(stale))
+	},
+}
+
+func init() {
+	purgeClonesCmd.Flags().String("older-than", "90d", "Age after which an unsynced clone counts as stale (e.g. 12w, 90d)")
+	purgeClonesCmd.Flags().Bool("yes", false, "Delete the stale clones (after confirmation) instead of only listing them")
+
+	rootCmd.AddCommand(purgeClonesCmd)
+}
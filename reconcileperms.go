@@ -0,0 +1,60 @@
+// reconcileperms.go
+//
+// Implements 'teryx reconcile-perms', which fixes the ownership and modes of a
+// repository that is already deployed on a server.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// reconcilePermsCmd handles the 'teryx reconcile-perms' command.
+var reconcilePermsCmd = &cobra.Command{
+	Use:   "reconcile-perms <user@host> <remote-path>",
+	Short: "Fixes the ownership and modes of a repository already on a server.",
+	Long: `Connects to the server over ssh and hands the repository file at <remote-path>,
+and the directory containing it, to the web server's user and group, using the
+same chown/chmod that 'teryx transfer' suggests after a transfer. Use this when
+the web server can no longer write to a deployed repository, without
+transferring it again. sudo may prompt for a password on the server.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		userHost := args[0]
+		remotePath := args[1]
+		remoteUser, _ := cmd.Flags().GetString("remote-user")
+		remoteMode, _ := cmd.Flags().GetString("remote-mode")
+		remoteDirMode, _ := cmd.Flags().GetString("remote-dir-mode")
+
+		if strings.Contains(userHost, ":") {
+			log.Fatalf("❌ Give the host as user@host and the repository path separately, not '%s'.", userHost)
+		}
+		sshOpts, err := transferSSHOptions(cmd)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		fmt.Printf("🚀 Reconciling permissions of %s on %s...\n", remotePath, userHost)
+		// -t allocates a terminal so that sudo can ask for a password.
+		sshArgs := append(append([]string{"-t"}, sshOpts...), userHost, fixPermsCommand(remoteUser, remoteMode, remoteDirMode, remotePath))
+		if err := executeCommand("", "ssh", sshArgs...); err != nil {
+			log.Fatalf("❌ Failed to update permissions: %v", err)
+		}
+
+		fmt.Printf("✅ Success! %s and its directory now belong to %s.\n", remotePath, remoteUser)
+	},
+}
+
+func init() {
+	reconcilePermsCmd.Flags().StringP("remote-user", "r", "www-data", "User/group for the web server on the remote host")
+	reconcilePermsCmd.Flags().String("remote-mode", "664", "Mode for the repository file")
+	reconcilePermsCmd.Flags().String("remote-dir-mode", "775", "Mode for the directory containing the repository")
+	reconcilePermsCmd.Flags().StringP("identity-file", "i", "", "SSH private key to use for ssh")
+	reconcilePermsCmd.Flags().String("bind-address", "", "Local IP address to connect from, for hosts with several interfaces")
+
+	rootCmd.AddCommand(reconcilePermsCmd)
+}
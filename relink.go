@@ -0,0 +1,67 @@
+// relink.go
+//
+// Implements 'teryx relink', which points an existing checkout at a repository
+// file that has been moved.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// relinkCmd handles the 'teryx relink' command.
+var relinkCmd = &cobra.Command{
+	Use:   "relink <checkout-dir> <new-repository-path>",
+	Short: "Points a checkout at its repository file's new location.",
+	Long: `Checkouts remember the absolute path of their repository file, so moving the
+file breaks them. relink updates the path stored in the checkout, which works
+even when the old file is already gone.
+
+Unlike closing and reopening the checkout, this keeps all local state: edits,
+and files added, removed or renamed but not yet committed. Any such pending
+changes are listed afterwards, so you can check that they survived.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkoutDir := args[0]
+
+		if !isCheckoutDir(checkoutDir) {
+			log.Fatalf("❌ '%s' is not the root of a Fossil checkout.", checkoutDir)
+		}
+		newRepoPath, err := filepath.Abs(args[1])
+		if err != nil {
+			log.Fatalf("❌ Invalid repository path: %v", err)
+		}
+		if _, err := os.Stat(newRepoPath); err != nil {
+			log.Fatalf("❌ Cannot read repository file: %v", err)
+		}
+
+		fmt.Printf("🚀 Relinking %s to %s...\n", checkoutDir, newRepoPath)
+		// test-move-repository rewrites the path in the checkout database
+		// without needing the old repository, which close/open would.
+		if err := executeCommand(checkoutDir, "fossil", "test-move-repository", newRepoPath); err != nil {
+			log.Fatalf("❌ Failed to relink checkout: %v", err)
+		}
+
+		changes, err := pendingChanges(checkoutDir)
+		if err != nil {
+			log.Fatalf("❌ The checkout was relinked, but fossil can't read it against %s. Is it the right repository? %v", newRepoPath, err)
+		}
+		if len(changes) > 0 {
+			fmt.Printf("⚠️ The checkout has %d uncommitted changes, which were kept:\n", len(changes))
+			for _, change := range changes {
+				fmt.Printf("   %-8s %s\n", change.Status, change.Path)
+			}
+		}
+
+		fmt.Printf("✅ Success! %s now uses %s.\n", checkoutDir, newRepoPath)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(relinkCmd)
+}
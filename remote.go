@@ -0,0 +1,184 @@
+// remote.go
+//
+// Implements the 'teryx remote' command group, which lists and edits the
+// named remotes of the open checkout's repository.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// fossilVersionPattern matches the version number in 'fossil version' output,
+// e.g. "This is fossil version 2.23 [47362306a7] 2023-11-01 18:56:47 UTC".
+var fossilVersionPattern = regexp.MustCompile(`version (\d+)\.(\d+)`)
+
+// fossilVersion returns the major and minor version of the installed fossil.
+func fossilVersion() (int, int, error) {
+	output, err := captureCommand("", "fossil", "version")
+	if err != nil {
+		return 0, 0, err
+	}
+	match := fossilVersionPattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return 0, 0, fmt.Errorf("could not find a version number in '%s'", strings.TrimSpace(string(output)))
+	}
+	major, _ := strconv.Atoi(match[1])
+	minor, _ := strconv.Atoi(match[2])
+	return major, minor, nil
+}
+
+// defaultRemoteName is the name fossil lists the remote-url under, which is
+// used for syncing unless another remote is named.
+const defaultRemoteName = "default"
+
+// hasNamedRemotes reports whether the installed fossil supports 'fossil remote
+// add/list/delete', which appeared in version 2.14. Older versions only have
+// the single remote-url.
+func hasNamedRemotes() (bool, error) {
+	major, minor, err := fossilVersion()
+	if err != nil {
+		return false, err
+	}
+	return major > 2 || (major == 2 && minor >= 14), nil
+}
+
+// remoteEntry is one line of 'fossil remote list'.
+type remoteEntry struct {
+	Name string
+	URL  string
+}
+
+// listRemotes returns the remotes of the open checkout's repository. Without
+// named remote support, the remote-url, if any, is returned as the default.
+func listRemotes(named bool) ([]remoteEntry, error) {
+	if !named {
+		output, err := captureCommand("", "fossil", "remote")
+		if err != nil {
+			return nil, err
+		}
+		remote := strings.TrimSpace(string(output))
+		if remote == "" || remote == "off" {
+			return nil, nil
+		}
+		return []remoteEntry{{defaultRemoteName, remote}}, nil
+	}
+
+	output, err := captureCommand("", "fossil", "remote", "list")
+	if err != nil {
+		return nil, err
+	}
+	var remotes []remoteEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			remotes = append(remotes, remoteEntry{fields[0], fields[1]})
+		}
+	}
+	return remotes, nil
+}
+
+// requireNamedRemotes returns whether named remotes are available, failing
+// if they aren't and name is anything but the default remote.
+func requireNamedRemotes(name string) bool {
+	named, err := hasNamedRemotes()
+	if err != nil {
+		log.Fatalf("❌ Could not determine the fossil version: %v", err)
+	}
+	if !named && name != defaultRemoteName {
+		log.Fatalf("❌ Named remotes need fossil 2.14 or later; this fossil only has the '%s' remote.", defaultRemoteName)
+	}
+	return named
+}
+
+// remoteCmd is the parent of the 'teryx remote' subcommands.
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Lists, adds and removes the remotes of the open checkout's repository.",
+	Long: `Manages the remotes of the open checkout's repository. Fossil 2.14 and later
+can store several named remotes besides the default one used by autosync;
+with older versions, only the 'default' remote (the remote-url) is available.`,
+	PersistentPreRunE: checkoutPreRun,
+}
+
+// remoteListCmd handles the 'teryx remote list' command.
+var remoteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the remotes, marking the default one.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		named := requireNamedRemotes(defaultRemoteName)
+		remotes, err := listRemotes(named)
+		if err != nil {
+			log.Fatalf("❌ Failed to list remotes: %v", err)
+		}
+		if len(remotes) == 0 {
+			fmt.Println("ℹ️  No remotes are configured.")
+			return
+		}
+		for _, remote := range remotes {
+			marker := " "
+			if remote.Name == defaultRemoteName {
+				marker = "*"
+			}
+			fmt.Printf("%s %-18s %s\n", marker, remote.Name, redactURL(remote.URL))
+		}
+	},
+}
+
+// remoteAddCmd handles the 'teryx remote add' command.
+var remoteAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Adds a named remote, or sets the default one.",
+	Long: `Adds a remote under the given name. The name 'default' sets the remote-url
+used by autosync instead, which also works with fossil versions before 2.14.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, remoteURL := args[0], args[1]
+		requireNamedRemotes(name)
+
+		fossilArgs := []string{"remote", "add", name, remoteURL}
+		if name == defaultRemoteName {
+			fossilArgs = []string{"remote", remoteURL}
+		}
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to add remote: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Remote '%s' now points at %s.\n", name, redactURL(remoteURL))
+	},
+}
+
+// remoteRemoveCmd handles the 'teryx remote remove' command.
+var remoteRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Removes a named remote, or turns off the default one.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		requireNamedRemotes(name)
+
+		fossilArgs := []string{"remote", "delete", name}
+		if name == defaultRemoteName {
+			fossilArgs = []string{"remote", "off"}
+		}
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to remove remote: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Remote '%s' removed.\n", name)
+	},
+}
+
+func init() {
+	remoteCmd.AddCommand(remoteListCmd)
+	remoteCmd.AddCommand(remoteAddCmd)
+	remoteCmd.AddCommand(remoteRemoveCmd)
+	rootCmd.AddCommand(remoteCmd)
+}
@@ -0,0 +1,177 @@
+// remotehash.go
+//
+// Helpers used by 'teryx transfer --verify' to compare a transferred file's
+// checksum on the remote host with the local one, using whichever checksum
+// tool the remote host has.
+
+package main
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// remoteHashTool is a checksum tool that may be installed on the remote host,
+// with the command that prints a file's checksum as its first word and the
+// local hash that yields the same value.
+type remoteHashTool struct {
+	Name    string
+	Command string
+	newHash func() hash.Hash
+}
+
+// remoteHashTools are the supported tools, in order of preference: SHA-256
+// where available, then MD5, then the POSIX CRC, which every system has.
+var remoteHashTools = []remoteHashTool{
+	{"sha256sum", "sha256sum", sha256.New},
+	{"shasum", "shasum -a 256", sha256.New},
+	{"md5sum", "md5sum", md5.New},
+	{"cksum", "cksum", newCksum},
+}
+
+// remoteHashToolNames returns the names accepted by --hash-algo.
+func remoteHashToolNames() []string {
+	var names []string
+	for _, tool := range remoteHashTools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// findRemoteHashTool returns the tool called name, or, with an empty name,
+// the most preferred tool installed on userHost, probing with 'command -v'.
+func findRemoteHashTool(sshOpts []string, userHost, name string) (remoteHashTool, error) {
+	if name != "" {
+		for _, tool := range remoteHashTools {
+			if tool.Name == name {
+				return tool, nil
+			}
+		}
+		return remoteHashTool{}, fmt.Errorf("unknown --hash-algo '%s'; use one of %s", name, strings.Join(remoteHashToolNames(), ", "))
+	}
+
+	// 'command -v' fails if any tool is missing, but still prints the others.
+	probe := "command -v " + strings.Join(remoteHashToolNames(), " ") + "; true"
+	output, err := captureCommand("", "ssh", append(append([]string{}, sshOpts...), userHost, probe)...)
+	if err != nil {
+		return remoteHashTool{}, fmt.Errorf("could not probe %s for checksum tools: %w", userHost, err)
+	}
+	var installed []string
+	for _, line := range strings.Split(string(output), "\n") {
+		installed = append(installed, path.Base(strings.TrimSpace(line)))
+	}
+	for _, tool := range remoteHashTools {
+		if slices.Contains(installed, tool.Name) {
+			return tool, nil
+		}
+	}
+	return remoteHashTool{}, fmt.Errorf("%s has none of %s", userHost, strings.Join(remoteHashToolNames(), ", "))
+}
+
+// localChecksum returns the checksum of a local file as tool prints it.
+func localChecksum(tool remoteHashTool, filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := tool.newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	if c, ok := h.(*cksum); ok {
+		return strconv.FormatUint(uint64(c.Sum32()), 10), nil
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// remoteChecksum runs tool on a file of userHost and returns the checksum.
+func remoteChecksum(sshOpts []string, userHost string, tool remoteHashTool, remotePath string) (string, error) {
+	output, err := captureCommand("", "ssh", append(append([]string{}, sshOpts...), userHost, tool.Command+" "+remoteShellPath(remotePath))...)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s printed nothing for %s", tool.Name, remotePath)
+	}
+	return fields[0], nil
+}
+
+// verifyTransfer compares the checksums of a local file and its remote copy.
+func verifyTransfer(sshOpts []string, userHost string, tool remoteHashTool, localPath, remotePath string) error {
+	local, err := localChecksum(tool, localPath)
+	if err != nil {
+		return fmt.Errorf("could not checksum %s: %w", localPath, err)
+	}
+	remote, err := remoteChecksum(sshOpts, userHost, tool, remotePath)
+	if err != nil {
+		return fmt.Errorf("could not checksum the remote copy with %s: %w", tool.Name, err)
+	}
+	if local != remote {
+		return fmt.Errorf("%s mismatch for %s: local %s, remote %s", tool.Name, remotePath, local, remote)
+	}
+	return nil
+}
+
+// cksum computes the POSIX cksum CRC: CRC-32 with polynomial 0x04C11DB7,
+// processed most significant bit first, over the data followed by its length.
+type cksum struct {
+	crc    uint32
+	length uint64
+}
+
+// cksumTable is the byte-wise lookup table of the cksum CRC.
+var cksumTable = func() (table [256]uint32) {
+	for i := range table {
+		crc := uint32(i) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04C11DB7
+			} else {
+				crc <<= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}()
+
+func newCksum() hash.Hash { return &cksum{} }
+
+func (c *cksum) update(b byte) { c.crc = c.crc<<8 ^ cksumTable[byte(c.crc>>24)^b] }
+
+func (c *cksum) Write(p []byte) (int, error) {
+	for _, b := range p {
+		c.update(b)
+	}
+	c.length += uint64(len(p))
+	return len(p), nil
+}
+
+// Sum32 returns the CRC of the data written so far, without changing it.
+func (c *cksum) Sum32() uint32 {
+	final := *c
+	for n := c.length; n > 0; n >>= 8 {
+		final.update(byte(n))
+	}
+	return ^final.crc
+}
+
+func (c *cksum) Sum(b []byte) []byte {
+	sum := c.Sum32()
+	return append(b, byte(sum>>24), byte(sum>>16), byte(sum>>8), byte(sum))
+}
+
+func (c *cksum) Reset()         { *c = cksum{} }
+func (c *cksum) Size() int      { return 4 }
+func (c *cksum) BlockSize() int { return 1 }
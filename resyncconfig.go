@@ -0,0 +1,149 @@
+// resyncconfig.go
+//
+// Implements 'teryx resync-config', which updates the URL settings stored in
+// a repository after it has been moved to a new server.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// repoSettingsRunner runs 'fossil settings' on a repository file, either
+// locally or, when userHost is set, over ssh on that host.
+type repoSettingsRunner struct {
+	RepoPath string
+	UserHost string
+	SSHOpts  []string
+}
+
+// run runs 'fossil settings <args...> -R <repo>' and returns its output.
+func (r repoSettingsRunner) run(args ...string) (string, error) {
+	fossilArgs := append(append([]string{"settings"}, args...), "-R", r.RepoPath)
+	if r.UserHost == "" {
+		output, err := captureCommand("", "fossil", fossilArgs...)
+		return string(output), err
+	}
+	quoted := make([]string, len(fossilArgs))
+	for i, arg := range fossilArgs {
+		quoted[i] = shellQuote(arg)
+	}
+	sshArgs := append(append([]string{}, r.SSHOpts...), r.UserHost, "fossil "+strings.Join(quoted, " "))
+	output, err := captureCommand("", "ssh", sshArgs...)
+	return string(output), err
+}
+
+// get returns a setting's value in the repository, and whether it is set there.
+func (r repoSettingsRunner) get(name string) (string, bool, error) {
+	output, err := r.run(name)
+	if err != nil {
+		return "", false, err
+	}
+	value, found := settingValue(output, "(local)")
+	return value, found, nil
+}
+
+// resyncedSettings returns the new values of the repository's URL settings for
+// a move to baseURL: email-url, fossil's canonical server URL used in
+// notification links, and, if its domain was the old server's, the domain of
+// the email-self sender address.
+func resyncedSettings(r repoSettingsRunner, baseURL *url.URL) (map[string][2]string, error) {
+	changes := make(map[string][2]string)
+	oldURL, _, err := r.get("email-url")
+	if err != nil {
+		return nil, err
+	}
+	if oldURL != baseURL.String() {
+		changes["email-url"] = [2]string{oldURL, baseURL.String()}
+	}
+
+	sender, ok, err := r.get("email-self")
+	if err != nil {
+		return nil, err
+	}
+	if parsedOld, err := url.Parse(oldURL); ok && err == nil && parsedOld.Hostname() != "" {
+		if local, domain, found := strings.Cut(sender, "@"); found && domain == parsedOld.Hostname() && domain != baseURL.Hostname() {
+			changes["email-self"] = [2]string{sender, local + "@" + baseURL.Hostname()}
+		}
+	}
+	return changes, nil
+}
+
+// resyncConfigCmd handles the 'teryx resync-config' command.
+var resyncConfigCmd = &cobra.Command{
+	Use:   "resync-config <repository-file>",
+	Short: "Updates a moved repository's URL settings to its new server.",
+	Long: `Repositories remember the URL they are served from, for the links in email
+notifications, so moving one to another server leaves them pointing at the old
+one. resync-config sets email-url (the canonical server URL) to --baseurl and,
+if the email-self sender address was at the old server's domain, moves it to
+the new one. Each setting that changed is reported.
+
+With --remote user@host, the repository file is a path on that host and the
+settings are changed there over ssh.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		baseURLFlag, _ := cmd.Flags().GetString("baseurl")
+		userHost, _ := cmd.Flags().GetString("remote")
+
+		if baseURLFlag == "" {
+			log.Fatal("❌ --baseurl flag is required.")
+		}
+		normalized, err := normalizeRemoteURL(strings.TrimSuffix(baseURLFlag, "/"))
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		baseURL, _ := url.Parse(normalized)
+		sshOpts, err := transferSSHOptions(cmd)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		if userHost == "" {
+			if _, err := os.Stat(args[0]); err != nil {
+				log.Fatalf("❌ Cannot read repository file: %v", err)
+			}
+		}
+
+		runner := repoSettingsRunner{RepoPath: args[0], UserHost: userHost, SSHOpts: sshOpts}
+		changes, err := resyncedSettings(runner, baseURL)
+		if err != nil {
+			log.Fatalf("❌ Failed to read the repository settings: %v", err)
+		}
+		if len(changes) == 0 {
+			fmt.Println("✅ All URL settings already match; nothing changed.")
+			return
+		}
+
+		for _, name := range []string{"email-url", "email-self"} {
+			change, ok := changes[name]
+			if !ok {
+				continue
+			}
+			if _, err := runner.run(name, change[1]); err != nil {
+				log.Fatalf("❌ Failed to set %s: %v", name, err)
+			}
+			old := change[0]
+			if old == "" {
+				old = "(unset)"
+			}
+			fmt.Printf("   %-10s %s → %s\n", name, old, change[1])
+		}
+		fmt.Printf("✅ Success! %d settings updated.\n", len(changes))
+	},
+}
+
+func init() {
+	resyncConfigCmd.Flags().String("baseurl", "", "URL the repository is now served from (required)")
+	resyncConfigCmd.Flags().String("remote", "", "Change a repository on this user@host over ssh")
+	resyncConfigCmd.Flags().StringP("identity-file", "i", "", "SSH private key to use with --remote")
+	resyncConfigCmd.Flags().String("bind-address", "", "Local IP address to connect from with --remote")
+
+	rootCmd.AddCommand(resyncConfigCmd)
+}
@@ -0,0 +1,88 @@
+// rmcheckout.go
+//
+// Implements 'teryx rm-checkout', which tears down a checkout created by
+// 'teryx init' or 'teryx clone' and, optionally, its repository file.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// rmCheckoutCmd handles the 'teryx rm-checkout' command.
+var rmCheckoutCmd = &cobra.Command{
+	Use:   "rm-checkout <checkout-dir>",
+	Short: "Closes a checkout and deletes its directory (and optionally the repo file).",
+	Long: `Closes the checkout in the given directory, deletes the directory and, with
+--remove-repo, the repository file it was opened against.
+
+Checkouts with uncommitted changes are refused unless --force is given. You are
+asked to confirm before anything is deleted; --force skips the question.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		checkoutDir := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+		removeRepo, _ := cmd.Flags().GetBool("remove-repo")
+
+		// Insist on the checkout root itself, so a subdirectory of a checkout
+		// is never mistaken for the whole thing.
+		if !isCheckoutDir(checkoutDir) {
+			log.Fatalf("❌ '%s' is not the root of a Fossil checkout.", checkoutDir)
+		}
+
+		repoPath, err := checkoutRepository(checkoutDir)
+		if err != nil {
+			log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+		}
+
+		dirty, err := hasUncommittedChanges(checkoutDir)
+		if err != nil {
+			log.Fatalf("❌ Could not check for uncommitted changes: %v", err)
+		}
+		if dirty && !force {
+			log.Fatalf("❌ '%s' has uncommitted changes. Commit them first or use --force to discard them.", checkoutDir)
+		}
+
+		fmt.Printf("ℹ️  Checkout directory: %s\n", checkoutDir)
+		if removeRepo {
+			fmt.Printf("ℹ️  Repository file:    %s\n", repoPath)
+		}
+		if !force && !confirm("Permanently delete the above?") {
+			fmt.Println("ℹ️  Aborted; nothing was removed.")
+			return
+		}
+
+		closeArgs := []string{"close"}
+		if force {
+			closeArgs = append(closeArgs, "--force")
+		}
+		if err := executeCommand(checkoutDir, "fossil", closeArgs...); err != nil {
+			log.Fatalf("❌ Failed to close checkout: %v", err)
+		}
+
+		if err := os.RemoveAll(checkoutDir); err != nil {
+			log.Fatalf("❌ Failed to remove checkout directory: %v", err)
+		}
+		fmt.Printf("🗑️  Removed %s\n", checkoutDir)
+
+		if removeRepo {
+			if err := os.Remove(repoPath); err != nil {
+				log.Fatalf("❌ Failed to remove repository file: %v", err)
+			}
+			fmt.Printf("🗑️  Removed %s\n", repoPath)
+		}
+
+		fmt.Println("✅ Success! Checkout removed.")
+	},
+}
+
+func init() {
+	rmCheckoutCmd.Flags().BoolP("force", "f", false, "Discard uncommitted changes and skip the confirmation prompt")
+	rmCheckoutCmd.Flags().Bool("remove-repo", false, "Also delete the repository file the checkout was opened against")
+
+	rootCmd.AddCommand(rmCheckoutCmd)
+}
@@ -0,0 +1,107 @@
+// scrub.go
+//
+// Implements 'teryx scrub', a guarded wrapper around 'fossil scrub' for
+// removing sensitive content before a repository is published.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// scrubCmd handles the 'teryx scrub' command.
+var scrubCmd = &cobra.Command{
+	Use:   "scrub [repository-file]",
+	Short: "Strips passwords and, optionally, private content from a repository.",
+	Long: `Wraps 'fossil scrub', which removes user passwords and other sensitive
+information from a repository, for the given repository file or that of the
+open checkout. --private also removes private branches, and --verily removes
+even more (concealed email addresses, IP addresses, the RCVFROM table).
+
+Scrubbing cannot be undone, so by default a copy named <name>-scrubbed.fossil
+(or --output) is scrubbed and the original left untouched; --in-place scrubs
+the repository itself. You are always asked to confirm.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: rejectFlagConflicts(checkoutOrRepositoryPreRun, [2]string{"in-place", "output"}),
+	Run: func(cmd *cobra.Command, args []string) {
+		private, _ := cmd.Flags().GetBool("private")
+		verily, _ := cmd.Flags().GetBool("verily")
+		inPlace, _ := cmd.Flags().GetBool("in-place")
+		output, _ := cmd.Flags().GetString("output")
+
+		repoPath := ""
+		if len(args) > 0 {
+			repoPath = args[0]
+		} else {
+			var err error
+			if repoPath, err = checkoutRepository(""); err != nil {
+				log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+			}
+		}
+		if _, err := os.Stat(repoPath); err != nil {
+			log.Fatalf("❌ Cannot read repository file: %v", err)
+		}
+
+		target := repoPath
+		if !inPlace {
+			target = output
+			if target == "" {
+				target = strings.TrimSuffix(repoPath, ".fossil") + "-scrubbed.fossil"
+			}
+			if _, err := os.Stat(target); err == nil {
+				log.Fatalf("❌ '%s' already exists.", target)
+			}
+		}
+
+		what := "passwords and other sensitive data"
+		if private {
+			what += ", and all private branches,"
+		}
+		if inPlace {
+			fmt.Printf("⚠️ This permanently removes %s from %s itself.\n", what, repoPath)
+		} else {
+			fmt.Printf("ℹ️  %s will be copied to %s, and %s removed from the copy.\n", repoPath, target, what)
+		}
+		if !confirm("Scrubbing cannot be undone. Continue?") {
+			fmt.Println("ℹ️  Aborted; nothing was changed.")
+			return
+		}
+
+		if !inPlace {
+			if err := copyFile(repoPath, target); err != nil {
+				log.Fatalf("❌ Failed to copy repository: %v", err)
+			}
+		}
+
+		// teryx has already asked, so fossil's own prompt is skipped with --force.
+		fossilArgs := []string{"scrub", "--force"}
+		if private {
+			fossilArgs = append(fossilArgs, "--private")
+		}
+		if verily {
+			fossilArgs = append(fossilArgs, "--verily")
+		}
+		fossilArgs = append(fossilArgs, target)
+
+		fmt.Printf("🚀 Scrubbing %s...\n", target)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to scrub repository: %v", err)
+		}
+
+		fmt.Printf("✅ Success! %s has been scrubbed.\n", target)
+	},
+}
+
+func init() {
+	scrubCmd.Flags().Bool("private", false, "Also remove private branches and other private content")
+	scrubCmd.Flags().Bool("verily", false, "Scrub more thoroughly (see 'fossil help scrub')")
+	scrubCmd.Flags().Bool("in-place", false, "Scrub the repository itself instead of a copy")
+	scrubCmd.Flags().StringP("output", "o", "", "Path of the scrubbed copy (default <name>-scrubbed.fossil)")
+
+	rootCmd.AddCommand(scrubCmd)
+}
@@ -0,0 +1,68 @@
+// search.go
+//
+// Implements 'teryx search', which finds check-ins whose comments contain a
+// search term.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// searchCmd handles the 'teryx search' command.
+var searchCmd = &cobra.Command{
+	Use:     "search <term>",
+	Aliases: []string{"grep"},
+	Short:   "Finds check-ins whose comment contains a term.",
+	Long: `Searches the check-in comments of the open checkout, or the repository given
+with -R, for a case-insensitive term and prints the matching check-ins with
+their hashes and dates, newest first.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		term := strings.ToLower(args[0])
+		limit, _ := cmd.Flags().GetInt("limit")
+		branch, _ := cmd.Flags().GetString("branch")
+
+		// Fetch the whole timeline and filter here, as fossil's own 'search'
+		// command only works when the full-text index has been enabled.
+		timelineArgs := []string{"-n", "0"}
+		if branch != "" {
+			timelineArgs = append(timelineArgs, "--branch", branch)
+		}
+		timelineArgs = append(timelineArgs, repositoryArgs(cmd, nil)...)
+
+		entries, err := fetchTimeline(timelineArgs...)
+		if err != nil {
+			log.Fatalf("❌ Failed to read timeline: %v", err)
+		}
+
+		matches := 0
+		for _, entry := range entries {
+			if !strings.Contains(strings.ToLower(entry.Comment), term) {
+				continue
+			}
+			fmt.Printf("%s  %s  %s\n", entry.Hash[:min(10, len(entry.Hash))], entry.Date, entry.Comment)
+			matches++
+			if limit > 0 && matches >= limit {
+				break
+			}
+		}
+
+		if matches == 0 {
+			fmt.Printf("ℹ️  No check-ins found matching '%s'.\n", args[0])
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(searchCmd)
+	searchCmd.Flags().IntP("limit", "n", 0, "Maximum number of matches to show (0 for no limit)")
+	searchCmd.Flags().StringP("branch", "b", "", "Only search check-ins on this branch")
+
+	rootCmd.AddCommand(searchCmd)
+}
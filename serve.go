@@ -0,0 +1,67 @@
+// serve.go
+//
+// Helpers for serving a repository with 'fossil server', either in the
+// foreground or via a generated systemd unit.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// serveRepository runs 'fossil server' for repoPath on the given port in the
+// foreground until it is interrupted.
+func serveRepository(repoPath string, port int) error {
+	fmt.Printf("🌐 Serving %s at http://localhost:%d/ (press Ctrl-C to stop)\n", repoPath, port)
+	return executeCommand("", "fossil", "server", "--port", strconv.Itoa(port), repoPath)
+}
+
+// systemdUnit returns the contents of a systemd service unit that serves
+// repoPath on port as the given system account. Without one, the unit has no
+// User= line and systemd runs it as root.
+func systemdUnit(repoPath string, port int, serviceUser string) (string, error) {
+	fossilPath, err := exec.LookPath("fossil")
+	if err != nil {
+		return "", fmt.Errorf("could not locate the fossil binary: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(repoPath), ".fossil")
+	userLine := ""
+	if serviceUser != "" {
+		userLine = "User=" + serviceUser + "\n"
+	}
+
+	return fmt.Sprintf(`[Unit]
+Description=Fossil server for %s
+After=network.target
+
+[Service]
+%sExecStart=%s server --port %d %s
+Restart=always
+
+[Install]
+WantedBy=multi-user.target
+`, name, userLine, fossilPath, port, repoPath), nil
+}
+
+// writeSystemdUnit writes a unit from systemdUnit to unitPath and prints the
+// commands needed to enable it.
+func writeSystemdUnit(unitPath, repoPath string, port int, serviceUser string) error {
+	unit, err := systemdUnit(repoPath, port, serviceUser)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return err
+	}
+
+	unitName := filepath.Base(unitPath)
+	fmt.Printf("✅ Success! systemd unit written to %s\n", unitPath)
+	fmt.Println("To install and start it, run:")
+	fmt.Printf("sudo cp %s /etc/systemd/system/%s && sudo systemctl daemon-reload && sudo systemctl enable --now %s\n", unitPath, unitName, unitName)
+	return nil
+}
@@ -0,0 +1,92 @@
+// serverlogs.go
+//
+// Implements 'teryx server-logs', which shows the output of a fossil server
+// on a remote host over ssh, from a log file or the systemd journal.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// serverLogsCommand returns the remote command that prints the last lines of
+// a fossil server's output: 'journalctl' for a systemd unit, or 'tail' for a
+// log file. follow keeps printing new lines as they arrive.
+func serverLogsCommand(logPath, unit string, lines int, follow bool) string {
+	if unit != "" {
+		command := fmt.Sprintf("journalctl --no-pager -u %s -n %d", shellQuote(unit), lines)
+		if follow {
+			command += " -f"
+		}
+		return command
+	}
+	command := fmt.Sprintf("tail -n %d", lines)
+	if follow {
+		command += " -F"
+	}
+	return command + " " + remoteShellPath(logPath)
+}
+
+// serverLogsCmd handles the 'teryx server-logs' command.
+var serverLogsCmd = &cobra.Command{
+	Use:   "server-logs <user@host>",
+	Short: "Shows the output of a fossil server on a remote host.",
+	Long: `Connects to the host over ssh and prints the last --lines lines of a fossil
+server's output, from one of two places:
+
+  --unit      the systemd journal of a unit, such as one written by
+              'teryx init --serve --systemd-unit' (via journalctl)
+  --log-path  a log file, e.g. one given to 'fossil server --errorlog'
+              (via tail)
+
+-f keeps following the output until Ctrl-C. Reading the journal of a system
+unit may require the remote user to be in the systemd-journal or adm group.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: rejectFlagConflicts(nil, [2]string{"log-path", "unit"}),
+	Run: func(cmd *cobra.Command, args []string) {
+		userHost := args[0]
+		logPath, _ := cmd.Flags().GetString("log-path")
+		unit, _ := cmd.Flags().GetString("unit")
+		lines, _ := cmd.Flags().GetInt("lines")
+		follow, _ := cmd.Flags().GetBool("follow")
+
+		if strings.Contains(userHost, ":") {
+			log.Fatalf("❌ Give the host as user@host and the log with --log-path or --unit, not '%s'.", userHost)
+		}
+		if logPath == "" && unit == "" {
+			log.Fatal("❌ Say where the server logs to: --unit <systemd-unit> or --log-path <file>.")
+		}
+		if lines < 1 {
+			log.Fatal("❌ --lines must be at least 1.")
+		}
+		sshOpts, err := transferSSHOptions(cmd)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		sshArgs := append([]string{}, sshOpts...)
+		if follow {
+			// -t makes Ctrl-C stop the remote command along with ssh.
+			sshArgs = append([]string{"-t"}, sshArgs...)
+		}
+		sshArgs = append(sshArgs, userHost, serverLogsCommand(logPath, unit, lines, follow))
+		if err := executeCommand("", "ssh", sshArgs...); err != nil {
+			log.Fatalf("❌ Failed to read the server logs: %v", err)
+		}
+	},
+}
+
+func init() {
+	serverLogsCmd.Flags().String("log-path", "", "Log file of the fossil server on the remote host")
+	serverLogsCmd.Flags().String("unit", "", "systemd unit running the fossil server, e.g. fossil-project.service")
+	serverLogsCmd.Flags().IntP("lines", "n", 50, "Number of lines to show")
+	serverLogsCmd.Flags().BoolP("follow", "f", false, "Keep printing new output until interrupted")
+	serverLogsCmd.Flags().StringP("identity-file", "i", "", "SSH private key to use for ssh")
+	serverLogsCmd.Flags().String("bind-address", "", "Local IP address to connect from, for hosts with several interfaces")
+
+	rootCmd.AddCommand(serverLogsCmd)
+}
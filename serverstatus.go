@@ -0,0 +1,100 @@
+// serverstatus.go
+//
+// Implements 'teryx server-status', which checks whether a remote fossil server
+// is responding and, where its JSON API is enabled, reports what it serves.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// fossilJSONResponse is the envelope fossil wraps every /json response in.
+type fossilJSONResponse struct {
+	ResultCode string          `json:"resultCode"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// fetchFossilJSON requests the given /json endpoint below baseURL and decodes
+// its payload into v. It fails if the server doesn't speak fossil's JSON API.
+func fetchFossilJSON(client *http.Client, baseURL, endpoint string, v any) error {
+	resp, err := client.Get(baseURL + "/json/" + endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var envelope fossilJSONResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("response is not fossil JSON: %w", err)
+	}
+	if envelope.ResultCode != "" {
+		return fmt.Errorf("fossil returned %s", envelope.ResultCode)
+	}
+	return json.Unmarshal(envelope.Payload, v)
+}
+
+// serverStatusCmd handles the 'teryx server-status' command.
+var serverStatusCmd = &cobra.Command{
+	Use:   "server-status <fossil-url>",
+	Short: "Checks whether a remote fossil server is up and serving the repo.",
+	Long: `Performs an HTTP request against a fossil server and reports whether it is
+responding. If the server has fossil's JSON API enabled, the served project
+name and fossil version are reported too.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		// Same normalization as clone: the web UI's '/home' is not part of the repo URL.
+		baseURL := strings.TrimSuffix(strings.TrimSuffix(args[0], "/"), "/home")
+		client := &http.Client{Timeout: timeout}
+
+		fmt.Printf("🚀 Checking server at '%s'...\n", baseURL)
+
+		start := time.Now()
+		resp, err := client.Get(baseURL)
+		if err != nil {
+			log.Fatalf("❌ Server is not responding: %v", err)
+		}
+		resp.Body.Close()
+		latency := time.Since(start)
+
+		if resp.StatusCode >= 500 {
+			log.Fatalf("❌ Server responded with %s", resp.Status)
+		}
+		fmt.Printf("✅ Server is responding (%s in %s).\n", resp.Status, latency.Round(time.Millisecond))
+
+		var version struct {
+			ReleaseVersion string `json:"releaseVersion"`
+			ManifestDate   string `json:"manifestDate"`
+		}
+		if err := fetchFossilJSON(client, baseURL, "version", &version); err != nil {
+			fmt.Printf("ℹ️  Fossil JSON API not available (%v); skipping project details.\n", err)
+			return
+		}
+		fmt.Printf("ℹ️  Fossil version: %s (%s)\n", version.ReleaseVersion, version.ManifestDate)
+
+		var stat struct {
+			ProjectName string `json:"projectName"`
+		}
+		if err := fetchFossilJSON(client, baseURL, "stat", &stat); err == nil && stat.ProjectName != "" {
+			fmt.Printf("ℹ️  Project name: %s\n", stat.ProjectName)
+		}
+	},
+}
+
+func init() {
+	serverStatusCmd.Flags().Duration("timeout", 10*time.Second, "Maximum time to wait for each HTTP request")
+
+	rootCmd.AddCommand(serverStatusCmd)
+}
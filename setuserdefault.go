@@ -0,0 +1,65 @@
+// setuserdefault.go
+//
+// Implements 'teryx set-user-default', which changes the user fossil commands
+// run as by default, as 'teryx init' sets it up for a new checkout.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// setUserDefaultCmd handles the 'teryx set-user-default' command.
+var setUserDefaultCmd = &cobra.Command{
+	Use:   "set-user-default <username>",
+	Short: "Changes the default user of the open checkout or a repository.",
+	Long: `Wraps 'fossil user default', which sets the user that commits and other
+commands are attributed to when no user is given. Applies to the open checkout,
+or to the repository given with -R. The user must exist in the repository;
+the previous and new default are printed.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkoutOrRepositoryPreRun(cmd, nil)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		username := args[0]
+		repoArgs := repositoryArgs(cmd, nil)
+
+		users, err := listRepoUsers(repoArgs)
+		if err != nil {
+			log.Fatalf("❌ Failed to list repository users: %v", err)
+		}
+		if !slices.Contains(users, username) {
+			log.Fatalf("❌ '%s' is not a user of this repository; known users: %s", username, strings.Join(users, ", "))
+		}
+
+		output, err := captureCommand("", "fossil", append([]string{"user", "default"}, repoArgs...)...)
+		if err != nil {
+			log.Fatalf("❌ Failed to read the current default user: %v", err)
+		}
+		previous := strings.TrimSpace(string(output))
+		if previous == username {
+			fmt.Printf("ℹ️  '%s' is already the default user.\n", username)
+			return
+		}
+
+		if err := executeCommand("", "fossil", append([]string{"user", "default", username}, repoArgs...)...); err != nil {
+			log.Fatalf("❌ Failed to set the default user: %v", err)
+		}
+		if previous == "" {
+			previous = "(none)"
+		}
+		fmt.Printf("✅ Success! Default user changed from '%s' to '%s'.\n", previous, username)
+	},
+}
+
+func init() {
+	addRepositoryFlag(setUserDefaultCmd)
+
+	rootCmd.AddCommand(setUserDefaultCmd)
+}
@@ -0,0 +1,93 @@
+// snapshot.go
+//
+// Implements 'teryx snapshot', which writes a plain tar or zip archive of a
+// revision for distribution, wrapping 'fossil tarball' and 'fossil zip'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// snapshotFormats maps each --format to the fossil command that writes it and
+// the file extension of the default output name.
+var snapshotFormats = map[string][2]string{
+	"tar": {"tarball", ".tar.gz"},
+	"zip": {"zip", ".zip"},
+}
+
+// snapshotCmd handles the 'teryx snapshot' command.
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Writes a tar or zip archive of a revision, e.g. for a release.",
+	Long: `Archives the files of a revision, without any fossil metadata, using
+'fossil tarball' (--format tar, a gzip-compressed tarball) or 'fossil zip'.
+
+By default the checked-out revision of the open checkout is archived, or the
+tip of the repository given with -R. --rev archives another check-in, branch
+or tag instead. The archive is written to --output, or to <name>.tar.gz or
+<name>.zip in the current directory, where <name> is the repository's name
+followed by the revision when one is given. Files inside the archive are
+stored under a <name>/ directory.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+		rev, _ := cmd.Flags().GetString("rev")
+		repoArgs := repositoryArgs(cmd, args)
+
+		formatInfo, ok := snapshotFormats[format]
+		if !ok {
+			log.Fatalf("❌ Unsupported --format '%s'. Use 'tar' or 'zip'.", format)
+		}
+
+		repoPath := ""
+		if repoArgs != nil {
+			repoPath = repoArgs[1]
+		} else {
+			var err error
+			if repoPath, err = checkoutRepository(""); err != nil {
+				log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+			}
+		}
+		name := strings.TrimSuffix(filepath.Base(repoPath), ".fossil")
+		if rev != "" {
+			name += "-" + strings.ReplaceAll(rev, "/", "-")
+		}
+		if output == "" {
+			output = name + formatInfo[1]
+		}
+
+		version := rev
+		if version == "" {
+			// "current" needs a checkout; a bare repository has only its tip.
+			version = "current"
+			if repoArgs != nil {
+				version = "tip"
+			}
+		}
+
+		fmt.Printf("🚀 Archiving %s as %s...\n", version, output)
+		fossilArgs := append([]string{formatInfo[0], version, output, "--name", name}, repoArgs...)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to write archive: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Archive written to %s\n", output)
+	},
+}
+
+func init() {
+	addRepositoryFlag(snapshotCmd)
+	snapshotCmd.Flags().String("format", "tar", "Archive format: tar (gzip-compressed) or zip")
+	snapshotCmd.Flags().StringP("output", "o", "", "Archive file to write (default <name>.tar.gz or <name>.zip)")
+	snapshotCmd.Flags().String("rev", "", "Check-in, branch or tag to archive (default: the checked-out revision)")
+
+	rootCmd.AddCommand(snapshotCmd)
+}
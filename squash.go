@@ -0,0 +1,110 @@
+// squash.go
+//
+// Implements 'teryx squash', which copies the net changes of a branch onto a
+// new branch as a single check-in, leaving the original branch untouched.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// squashMessage builds the default commit message of a squashed branch from
+// its entries, which fetchTimeline returns newest first.
+func squashMessage(branch string, entries []timelineEntry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Squash of branch '%s' (%d check-ins):\n", branch, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "\n- %s", entries[i].Comment)
+	}
+	return b.String()
+}
+
+// squashCmd handles the 'teryx squash' command.
+var squashCmd = &cobra.Command{
+	Use:   "squash <branch>",
+	Short: "Copies a branch's net changes onto a new branch as one check-in.",
+	Long: `Creates a new branch, named with --name or "<branch>-squashed" by default,
+that starts where <branch> forked off and holds all of its changes as a single
+check-in. The commit message lists the comments of the squashed check-ins,
+oldest first, unless one is given with --message.
+
+This is not destructive: Fossil history is never rewritten, so the original
+branch and all of its check-ins remain in the repository. The new check-in is
+recorded as a merge of the original branch's tip, so the link between the two
+is visible in the timeline. Pass --close to close the original branch, which
+hides it from the list of open branches but keeps its history.
+
+The open checkout must have no uncommitted changes, and ends up on the new
+branch.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		branch := args[0]
+		name, _ := cmd.Flags().GetString("name")
+		message, _ := cmd.Flags().GetString("message")
+		closeBranch, _ := cmd.Flags().GetBool("close")
+		if name == "" {
+			name = branch + "-squashed"
+		}
+
+		dirty, err := hasUncommittedChanges("")
+		if err != nil {
+			log.Fatalf("❌ Could not check for uncommitted changes: %v", err)
+		}
+		if dirty {
+			log.Fatal("❌ The checkout has uncommitted changes. Commit or revert them before squashing.")
+		}
+
+		entries, err := fetchTimeline("-n", "0", "--branch", branch)
+		if err != nil {
+			log.Fatalf("❌ Could not read the timeline of branch '%s': %v", branch, err)
+		}
+		if len(entries) == 0 {
+			log.Fatalf("❌ Branch '%s' has no check-ins.", branch)
+		}
+		tip := entries[0].Hash
+		parent, err := fossilInfoField("", "parent", entries[len(entries)-1].Hash)
+		if err != nil {
+			log.Fatalf("❌ Could not find where branch '%s' forked off: %v", branch, err)
+		}
+		base, _, _ := strings.Cut(parent, " ")
+		if message == "" {
+			message = squashMessage(branch, entries)
+		}
+
+		fmt.Printf("🚀 Squashing %d check-ins of '%s' onto new branch '%s'...\n", len(entries), branch, name)
+		// Merging the tip into the fork point yields exactly the branch's
+		// net changes, which are then committed once on the new branch.
+		if err := executeCommand("", "fossil", "update", base); err != nil {
+			log.Fatalf("❌ Failed to update to the fork point %s: %v", base, err)
+		}
+		if err := executeCommand("", "fossil", "merge", tip); err != nil {
+			log.Fatalf("❌ Failed to merge '%s'; run 'fossil undo' to restore the checkout: %v", branch, err)
+		}
+		if err := executeCommand("", "fossil", "commit", "--branch", name, "-m", message); err != nil {
+			log.Fatalf("❌ Failed to commit the squashed changes; run 'fossil undo' to restore the checkout: %v", err)
+		}
+
+		if closeBranch {
+			if err := executeCommand("", "fossil", "branch", "close", branch); err != nil {
+				log.Fatalf("❌ Squashed onto '%s', but failed to close '%s': %v", name, branch, err)
+			}
+			fmt.Printf("ℹ️  Closed branch '%s'; its history is kept.\n", branch)
+		}
+
+		fmt.Printf("✅ Success! '%s' holds the changes of '%s' as one check-in.\n", name, branch)
+	},
+}
+
+func init() {
+	squashCmd.Flags().String("name", "", "Name of the new branch (default \"<branch>-squashed\")")
+	squashCmd.Flags().StringP("message", "m", "", "Commit message (default: the squashed check-ins' comments)")
+	squashCmd.Flags().Bool("close", false, "Close the original branch afterwards")
+
+	rootCmd.AddCommand(squashCmd)
+}
@@ -0,0 +1,117 @@
+// stat.go
+//
+// Implements 'teryx stat', which summarizes the size and content counts of a
+// repository from 'fossil dbstat'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// repoStat holds the metrics 'teryx stat' reports for a repository.
+type repoStat struct {
+	Project      string `json:"project"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Artifacts    int64  `json:"artifacts"`
+	CheckIns     int64  `json:"check_ins"`
+	Files        int64  `json:"files"`
+	WikiPages    int64  `json:"wiki_pages"`
+	Tickets      int64  `json:"tickets"`
+	Users        int    `json:"users"`
+	AgeDays      int64  `json:"age_days"`
+	LatestChange string `json:"latest_change"`
+}
+
+// parseDbstat reads the "key: value" lines printed by 'fossil dbstat' into a
+// map keyed by the lower-case, hyphenated key (e.g. "repository-size").
+func parseDbstat(output string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(key)), " ", "-")
+		values[key] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// leadingCount parses the number at the start of a dbstat value such as
+// "59,113 (stored as ...)" or "6,512 days or ...", returning 0 if there is none.
+func leadingCount(value string) int64 {
+	digits := strings.ReplaceAll(strings.Fields(value + " ")[0], ",", "")
+	n, _ := strconv.ParseInt(digits, 10, 64)
+	return n
+}
+
+// statCmd handles the 'teryx stat' command.
+var statCmd = &cobra.Command{
+	Use:   "stat [repository-file]",
+	Short: "Shows size and content metrics for a repository.",
+	Long: `Summarizes 'fossil dbstat' for the repository of the open checkout, or the
+repository file given as an argument or via -R: its size, the number of
+artifacts, check-ins, files, wiki pages, tickets and users, and its age. These
+are the numbers to look at when deciding whether to rebuild or compress a
+repository.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		repoArgs := repositoryArgs(cmd, args)
+
+		output, err := captureCommand("", "fossil", append([]string{"dbstat"}, repoArgs...)...)
+		if err != nil {
+			log.Fatalf("❌ Failed to read repository statistics: %v", err)
+		}
+		users, err := listRepoUsers(repoArgs)
+		if err != nil {
+			log.Fatalf("❌ Failed to list repository users: %v", err)
+		}
+
+		values := parseDbstat(string(output))
+		stat := repoStat{
+			Project:      values["project-name"],
+			SizeBytes:    leadingCount(values["repository-size"]),
+			Artifacts:    leadingCount(values["artifact-count"]),
+			CheckIns:     leadingCount(values["check-ins"]),
+			Files:        leadingCount(values["files"]),
+			WikiPages:    leadingCount(values["wiki-pages"]),
+			Tickets:      leadingCount(values["tickets"]),
+			Users:        len(users),
+			AgeDays:      leadingCount(values["project-age"]),
+			LatestChange: values["latest-change"],
+		}
+
+		if asJSON {
+			if err := printJSON(stat); err != nil {
+				log.Fatalf("❌ Failed to encode statistics: %v", err)
+			}
+			return
+		}
+
+		fmt.Printf("Project:        %s\n", stat.Project)
+		fmt.Printf("Size:           %s\n", formatBytes(stat.SizeBytes))
+		fmt.Printf("Artifacts:      %d\n", stat.Artifacts)
+		fmt.Printf("Check-ins:      %d\n", stat.CheckIns)
+		fmt.Printf("Files:          %d\n", stat.Files)
+		fmt.Printf("Wiki pages:     %d\n", stat.WikiPages)
+		fmt.Printf("Tickets:        %d\n", stat.Tickets)
+		fmt.Printf("Users:          %d\n", stat.Users)
+		fmt.Printf("Age:            %d days\n", stat.AgeDays)
+		fmt.Printf("Latest change:  %s\n", stat.LatestChange)
+	},
+}
+
+func init() {
+	addRepositoryFlag(statCmd)
+	statCmd.Flags().Bool("json", false, "Print the metrics as a JSON object")
+
+	rootCmd.AddCommand(statCmd)
+}
@@ -0,0 +1,168 @@
+// syncall.go
+//
+// Implements 'teryx sync-all', which syncs every repository found under the
+// fossils base directory against its configured remote.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// syncResult records the outcome of syncing a single repository.
+type syncResult struct {
+	repoPath string
+	err      error
+}
+
+// findRepoFiles walks baseDir and returns the paths of all .fossil files in it.
+func findRepoFiles(baseDir string) ([]string, error) {
+	var repos []string
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".fossil") {
+			repos = append(repos, path)
+		}
+		return nil
+	})
+	return repos, err
+}
+
+// syncRepo runs 'fossil sync' against a single repository file. When buffered
+// is true, the command's output is captured and printed in one block once it
+// finishes, so that concurrent syncs don't interleave their output.
+//
+// Mirrors set up with 'teryx clone --mirror' (autosync pullonly) are only
+// pulled, so nothing local is ever pushed back to their source.
+func syncRepo(repoPath string, buffered bool) error {
+	operation := "sync"
+	if autosync, _, _ := repoSetting(repoPath, "autosync"); autosync == "pullonly" {
+		operation = "pull"
+	}
+
+	if !buffered {
+		return executeCommand("", "fossil", operation, "-R", repoPath)
+	}
+
+	cmd := exec.Command("fossil", withFossilVerbosity("fossil", []string{operation, "-R", repoPath})...)
+	output, err := cmd.CombinedOutput()
+	fmt.Printf("▶️  Executed: %s\n%s", commandString(cmd), output)
+	if err != nil {
+		return fmt.Errorf("❌ command failed: %s", err)
+	}
+	return nil
+}
+
+// syncAllCmd handles the 'teryx sync-all' command.
+var syncAllCmd = &cobra.Command{
+	Use:   "sync-all",
+	Short: "Syncs every local clone under the fossils directory with its remote.",
+	Long: `Walks the fossils base directory ($HOME/fossils, or base-dir from the config
+file) and runs 'fossil sync' for every repository file found, using each
+repository's configured remote-url.
+Mirrors created with 'teryx clone --mirror' are pulled but never pushed.
+A per-repository summary is printed at the end.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+
+		if parallel < 1 {
+			log.Fatal("❌ --parallel must be at least 1.")
+		}
+
+		baseDir, err := fossilsBaseDir()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+
+		repos, err := findRepoFiles(baseDir)
+		if err != nil {
+			log.Fatalf("❌ Failed to scan '%s': %v", baseDir, err)
+		}
+		if len(repos) == 0 {
+			fmt.Printf("ℹ️  No repositories found under %s\n", baseDir)
+			return
+		}
+
+		fmt.Printf("🚀 Syncing %d repositories under '%s'...\n", len(repos), baseDir)
+
+		results := make([]syncResult, len(repos))
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			aborted bool
+		)
+		sem := make(chan struct{}, parallel)
+
+		for i, repo := range repos {
+			sem <- struct{}{}
+
+			mu.Lock()
+			stop := aborted
+			mu.Unlock()
+			if stop {
+				<-sem
+				break
+			}
+
+			wg.Add(1)
+			go func(i int, repo string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				err := syncRepo(repo, parallel > 1)
+				results[i] = syncResult{repoPath: repo, err: err}
+				if err != nil && !continueOnError {
+					mu.Lock()
+					aborted = true
+					mu.Unlock()
+				}
+			}(i, repo)
+		}
+		wg.Wait()
+
+		// Print the per-repository summary
+		failed := 0
+		fmt.Println("-----------------------------------------------------------------")
+		for _, result := range results {
+			if result.repoPath == "" {
+				continue // never started because the batch was aborted
+			}
+			relPath, _ := filepath.Rel(baseDir, result.repoPath)
+			if result.err != nil {
+				failed++
+				fmt.Printf("❌ %s: %v\n", relPath, result.err)
+			} else {
+				fmt.Printf("✅ %s\n", relPath)
+			}
+		}
+		fmt.Println("-----------------------------------------------------------------")
+
+		if aborted {
+			fmt.Fprintln(os.Stderr, "⚠️ Aborted after first failure. Use --continue-on-error to sync the remaining repositories.")
+		}
+		if failed > 0 {
+			log.Fatalf("❌ %d of %d repositories failed to sync.", failed, len(repos))
+		}
+		fmt.Printf("✅ Success! All %d repositories synced.\n", len(repos))
+	},
+}
+
+func init() {
+	syncAllCmd.Flags().Bool("continue-on-error", false, "Keep syncing the remaining repositories when one fails")
+	syncAllCmd.Flags().IntP("parallel", "j", 1, "Number of repositories to sync concurrently")
+
+	rootCmd.AddCommand(syncAllCmd)
+}
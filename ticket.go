@@ -0,0 +1,118 @@
+// ticket.go
+//
+// Implements the 'teryx ticket' command group, a thin wrapper around
+// 'fossil ticket' for browsing and filing tickets in the open checkout.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ticketTemplate seeds the editor when filing a new ticket.
+const ticketTemplate = `
+# Describe the ticket above. Lines starting with '#' are ignored.
+# An empty description aborts the ticket.
+`
+
+// ticketCmd is the parent of the 'teryx ticket' subcommands.
+var ticketCmd = &cobra.Command{
+	Use:               "ticket",
+	Short:             "Lists, shows and files tickets in the open checkout's repository.",
+	PersistentPreRunE: checkoutPreRun,
+}
+
+// ticketListCmd handles the 'teryx ticket list' command.
+var ticketListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists tickets using a ticket report.",
+	Long: `Runs 'fossil ticket show' for the given report. Report 0, the default, shows
+every column of every ticket.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		report, _ := cmd.Flags().GetString("report")
+
+		if err := executeCommand("", "fossil", "ticket", "show", report); err != nil {
+			log.Fatalf("❌ Failed to list tickets: %v", err)
+		}
+	},
+}
+
+// ticketShowCmd handles the 'teryx ticket show' command.
+var ticketShowCmd = &cobra.Command{
+	Use:   "show <ticket-id>",
+	Short: "Shows a single ticket. The id may be abbreviated.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ticketID := args[0]
+
+		// Ticket ids are hex hashes, so refuse anything else rather than
+		// splicing it into the SQL filter passed to fossil.
+		if ticketID == "" || strings.Trim(strings.ToLower(ticketID), "0123456789abcdef") != "" {
+			log.Fatalf("❌ Invalid ticket id '%s'. Expected a (possibly abbreviated) hex hash.", ticketID)
+		}
+
+		filter := fmt.Sprintf("tkt_uuid GLOB '%s*'", strings.ToLower(ticketID))
+		if err := executeCommand("", "fossil", "ticket", "show", "0", filter); err != nil {
+			log.Fatalf("❌ Failed to show ticket: %v", err)
+		}
+	},
+}
+
+// ticketAddCmd handles the 'teryx ticket add' command.
+var ticketAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Files a new ticket, opening your editor for its description.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		title, _ := cmd.Flags().GetString("title")
+		ticketType, _ := cmd.Flags().GetString("type")
+		severity, _ := cmd.Flags().GetString("severity")
+
+		if title == "" {
+			log.Fatal("❌ --title flag is required.")
+		}
+
+		description, err := editText(ticketTemplate)
+		if err != nil {
+			log.Fatalf("❌ Failed to edit ticket description: %v", err)
+		}
+		if description == "" {
+			log.Fatal("❌ Aborting ticket due to empty description.")
+		}
+
+		fossilArgs := []string{"ticket", "add",
+			"title", title,
+			"comment", description,
+			"status", "Open",
+			"type", ticketType,
+		}
+		if severity != "" {
+			fossilArgs = append(fossilArgs, "severity", severity)
+		}
+
+		fmt.Printf("🚀 Filing ticket '%s'...\n", title)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to add ticket: %v", err)
+		}
+
+		fmt.Println("✅ Success! Ticket filed.")
+	},
+}
+
+func init() {
+	ticketListCmd.Flags().String("report", "0", "Ticket report number or title to list")
+
+	ticketAddCmd.Flags().StringP("title", "t", "", "Title of the new ticket (required)")
+	ticketAddCmd.Flags().String("type", "Code_Defect", "Ticket type, e.g. Code_Defect, Feature_Request")
+	ticketAddCmd.Flags().String("severity", "", "Ticket severity, e.g. Critical, Minor")
+
+	ticketCmd.AddCommand(ticketListCmd)
+	ticketCmd.AddCommand(ticketShowCmd)
+	ticketCmd.AddCommand(ticketAddCmd)
+	rootCmd.AddCommand(ticketCmd)
+}
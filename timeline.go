@@ -0,0 +1,164 @@
+// timeline.go
+//
+// Implements 'teryx timeline', a wrapper around 'fossil timeline' with
+// friendlier limit, branch and date filters.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// timelineDateLayouts are the absolute date formats accepted by --since.
+var timelineDateLayouts = []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+
+// parseSince turns a --since value into the date string passed to fossil. It
+// accepts an absolute date (e.g. 2024-01-01) or a relative age as understood
+// by parseAge, e.g. 7d.
+func parseSince(value string, now time.Time) (string, error) {
+	for _, layout := range timelineDateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t.Format("2006-01-02 15:04:05"), nil
+		}
+	}
+
+	if age, err := parseAge(value); err == nil {
+		return now.Add(-age).Format("2006-01-02 15:04:05"), nil
+	}
+	return "", fmt.Errorf("invalid --since value '%s'. Use a date like 2024-01-01 or an age like 12h, 7d or 2w", value)
+}
+
+// parseAge parses a relative age made of a number and a unit: h (hours),
+// d (days) or w (weeks), e.g. 12h, 7d or 2w.
+func parseAge(value string) (time.Duration, error) {
+	if len(value) >= 2 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err == nil && n >= 0 {
+			var unit time.Duration
+			switch value[len(value)-1] {
+			case 'h':
+				unit = time.Hour
+			case 'd':
+				unit = 24 * time.Hour
+			case 'w':
+				unit = 7 * 24 * time.Hour
+			}
+			if unit != 0 {
+				return time.Duration(n) * unit, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid age '%s'. Use a number and a unit, such as 12h, 7d or 2w", value)
+}
+
+// parseDateOverride validates a date given for fossil's --date-override and
+// returns it in the "YYYY-MM-DD HH:MM:SS" form fossil expects. The accepted
+// formats are those of --since, plus RFC 3339; a date without a zone is taken
+// to be UTC, which is how fossil stores times.
+func parseDateOverride(value string) (string, error) {
+	for _, layout := range append([]string{time.RFC3339}, timelineDateLayouts...) {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC().Format("2006-01-02 15:04:05"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date '%s'. Use a format like 2024-01-01, 2024-01-01 12:00:00 or 2024-01-01T12:00:00Z", value)
+}
+
+// timelineEntry is a single check-in parsed from 'fossil timeline' output.
+type timelineEntry struct {
+	Hash    string `json:"hash"`
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Branch  string `json:"branch"`
+	Comment string `json:"comment"`
+}
+
+// timelineFormat is the 'fossil timeline -F' format read by fetchTimeline.
+// Fossil replaces tabs and newlines in comments with spaces, so tabs are a
+// safe field separator.
+const timelineFormat = "%H\t%d\t%a\t%b\t%c"
+
+// fetchTimeline runs 'fossil timeline' for check-ins only, with any extra
+// arguments (e.g. "-n", "10" or "--branch", "trunk"), and parses the result.
+// Lines that aren't entries, such as the day separators, are skipped.
+func fetchTimeline(extraArgs ...string) ([]timelineEntry, error) {
+	fossilArgs := []string{"timeline", "-t", "ci", "-W", "0", "-F", timelineFormat}
+	output, err := captureCommand("", "fossil", append(append([]string{}, fossilArgs...), extraArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []timelineEntry
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		entries = append(entries, timelineEntry{
+			Hash:    fields[0],
+			Date:    fields[1],
+			Author:  fields[2],
+			Branch:  fields[3],
+			Comment: fields[4],
+		})
+	}
+	return entries, nil
+}
+
+// timelineCmd handles the 'teryx timeline' command.
+var timelineCmd = &cobra.Command{
+	Use:   "timeline",
+	Short: "Shows recent check-ins, optionally filtered by branch and date.",
+	Long: `Wraps 'fossil timeline' for the open checkout, or the repository given with -R.
+--since (or its alias --after) accepts a date such as 2024-01-01 or a relative
+age such as 12h, 7d or 2w.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		branch, _ := cmd.Flags().GetString("branch")
+		since, _ := cmd.Flags().GetString("since")
+
+		fossilArgs := []string{"timeline"}
+		if since != "" {
+			date, err := parseSince(since, time.Now())
+			if err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+			fossilArgs = append(fossilArgs, "after", date)
+		}
+		fossilArgs = append(fossilArgs, "-n", strconv.Itoa(limit))
+		if branch != "" {
+			fossilArgs = append(fossilArgs, "--branch", branch)
+		}
+		fossilArgs = append(fossilArgs, repositoryArgs(cmd, nil)...)
+
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to show timeline: %v", err)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(timelineCmd)
+	timelineCmd.Flags().IntP("limit", "n", 20, "Maximum number of entries to show (0 for no limit)")
+	timelineCmd.Flags().StringP("branch", "b", "", "Only show check-ins on this branch")
+	timelineCmd.Flags().String("since", "", "Only show entries after this date or age (e.g. 2024-01-01, 7d)")
+
+	// Accept --after as an alias for --since.
+	timelineCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "after" {
+			name = "since"
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	rootCmd.AddCommand(timelineCmd)
+}
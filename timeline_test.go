@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 20, 12, 30, 0, 0, time.Local)
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"2024-01-01", "2024-01-01 00:00:00", false},
+		{"2024-01-01 08:15", "2024-01-01 08:15:00", false},
+		{"2024-01-01 08:15:30", "2024-01-01 08:15:30", false},
+		{"2024-01-01T08:15:30", "2024-01-01 08:15:30", false},
+		{"12h", "2024-06-20 00:30:00", false},
+		{"7d", "2024-06-13 12:30:00", false},
+		{"2w", "2024-06-06 12:30:00", false},
+		{"0d", "2024-06-20 12:30:00", false},
+		{"-1d", "", true},
+		{"7", "", true},
+		{"7y", "", true},
+		{"d", "", true},
+		{"yesterday", "", true},
+		{"2024-02-30", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSince(tt.value, now)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseSince(%q) = %q, %v, want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+// transfer.go
+//
+// Helpers used by 'teryx transfer' for scp-style destinations, ssh options and
+// selecting the repository files to copy.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// validateIdentityFile checks that an ssh private key given with
+// --identity-file exists and is a regular file.
+func validateIdentityFile(identityFile string) error {
+	info, err := os.Stat(identityFile)
+	if err != nil {
+		return fmt.Errorf("identity file not usable: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("identity file '%s' is a directory", identityFile)
+	}
+	return nil
+}
+
+// transferSSHOptions returns the command-line options shared by the ssh, scp
+// and sftp invocations of a transfer, based on the command's flags.
+func transferSSHOptions(cmd *cobra.Command) ([]string, error) {
+	var opts []string
+	if identityFile, _ := cmd.Flags().GetString("identity-file"); identityFile != "" {
+		if err := validateIdentityFile(identityFile); err != nil {
+			return nil, err
+		}
+		opts = append(opts, "-i", identityFile)
+	}
+	if bindAddress, _ := cmd.Flags().GetString("bind-address"); bindAddress != "" {
+		if net.ParseIP(bindAddress) == nil {
+			return nil, fmt.Errorf("invalid --bind-address '%s': not an IP address", bindAddress)
+		}
+		// scp and sftp have no -b of their own, but all three accept the option form.
+		opts = append(opts, "-o", "BindAddress="+bindAddress)
+	}
+	return opts, nil
+}
+
+// splitDestination splits an scp-style user@host:path destination into its
+// user@host and remote path parts.
+func splitDestination(destination string) (userHost string, remotePath string, err error) {
+	parts := strings.SplitN(destination, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", fmt.Errorf("invalid destination '%s'. Expected user@host:path", destination)
+	}
+	return parts[0], parts[1], nil
+}
+
+// remoteURLHost returns the user@host (or just the host, when the URL names
+// no user) of the open checkout's remote-url, for 'transfer --dest-from-remote'.
+func remoteURLHost() (string, error) {
+	output, err := captureCommand("", "fossil", "remote")
+	if err != nil {
+		return "", fmt.Errorf("could not read the remote-url: %w", err)
+	}
+	remote := strings.TrimSpace(string(output))
+	if remote == "" || remote == "off" {
+		return "", errors.New("the open checkout has no remote-url; set one with 'teryx remote add default <url>' or pass --destination")
+	}
+	parsedURL, err := url.Parse(remote)
+	if err != nil || parsedURL.Hostname() == "" {
+		return "", fmt.Errorf("the remote-url '%s' names no host; pass --destination instead", redactURL(remote))
+	}
+	if parsedURL.User != nil && parsedURL.User.Username() != "" {
+		return parsedURL.User.Username() + "@" + parsedURL.Hostname(), nil
+	}
+	return parsedURL.Hostname(), nil
+}
+
+// destinationPlaceholders are the placeholders expanded in the remote path of
+// a transfer destination, described for error messages and help.
+var destinationPlaceholders = []string{"{date}", "{reponame}", "{host}"}
+
+// expandDestination replaces the placeholders in a destination's remote path:
+// {date} with the current date (YYYY-MM-DD), {reponame} with the repository
+// file's name without .fossil, and {host} with the local host name. Any other
+// {...} is an error, to catch typos before anything is copied.
+func expandDestination(remotePath, repoFile string) (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("could not determine the host name for {host}: %w", err)
+	}
+	expanded := strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02"),
+		"{reponame}", strings.TrimSuffix(filepath.Base(repoFile), ".fossil"),
+		"{host}", host,
+	).Replace(remotePath)
+	if start := strings.Index(expanded, "{"); start >= 0 && strings.Contains(expanded[start:], "}") {
+		end := start + strings.Index(expanded[start:], "}")
+		return "", fmt.Errorf("unknown placeholder '%s' in destination; use %s", expanded[start:end+1], strings.Join(destinationPlaceholders, ", "))
+	}
+	return expanded, nil
+}
+
+// remoteRepoPath returns the full remote path the repository file ends up at
+// after 'scp <repoName> host:<remotePath>'. A remotePath that is empty or ends
+// in '/' names a directory, as does one without a .fossil extension (scp keeps
+// the original filename when copying into an existing directory). A remotePath
+// ending in '.fossil' is taken to be the full target filename.
+func remoteRepoPath(remotePath, repoName string) string {
+	if strings.HasSuffix(remotePath, ".fossil") {
+		return remotePath
+	}
+	// Remote paths are always POSIX, so use path rather than filepath to join.
+	return path.Join(remotePath, filepath.Base(repoName))
+}
+
+// remoteRepoDir returns the remote directory the repository file ends up in,
+// as created by 'transfer --dest-mkdir'.
+func remoteRepoDir(remotePath, repoName string) string {
+	return path.Dir(remoteRepoPath(remotePath, repoName))
+}
+
+// atomicTempPath returns the temporary remote path 'transfer --atomic' uploads
+// finalPath to: a hidden file in the same directory, so the final 'mv' stays
+// on one filesystem and is atomic.
+func atomicTempPath(finalPath string) string {
+	return path.Join(path.Dir(finalPath), fmt.Sprintf(".%s.teryx-%d.tmp", path.Base(finalPath), os.Getpid()))
+}
+
+// renameRemoteFile moves from to to on userHost over ssh, replacing any file
+// already at to.
+func renameRemoteFile(sshOpts []string, userHost, from, to string) error {
+	return runRemoteCommand(sshOpts, userHost, "mv -f -- "+remoteShellPath(from)+" "+remoteShellPath(to))
+}
+
+// removeRemoteFile deletes a leftover file on userHost over ssh. Failures are
+// only reported, since it runs while handling another error.
+func removeRemoteFile(sshOpts []string, userHost, remotePath string) {
+	if err := runRemoteCommand(sshOpts, userHost, "rm -f -- "+remoteShellPath(remotePath)); err != nil {
+		fmt.Printf("⚠️ Could not remove the temporary file %s:%s: %v\n", userHost, remotePath, err)
+	}
+}
+
+// shellQuote quotes s for safe use as a single word in a POSIX shell command,
+// such as the remote command line passed to ssh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// remoteShellPath quotes a remote path for a shell command like shellQuote,
+// but leaves a leading "~/" outside the quotes so the remote shell still
+// expands it to the home directory, as scp does for the same destination.
+func remoteShellPath(remotePath string) string {
+	if remotePath == "~" {
+		return remotePath
+	}
+	if rest, ok := strings.CutPrefix(remotePath, "~/"); ok {
+		if rest == "" {
+			return "~/"
+		}
+		return "~/" + shellQuote(rest)
+	}
+	return shellQuote(remotePath)
+}
+
+// runRemoteCommand runs a shell command on userHost over ssh with the given
+// ssh options. The command's output is shown unless quiet is set.
+func runRemoteCommand(sshOpts []string, userHost, remoteCommand string) error {
+	sshArgs := append(append([]string{}, sshOpts...), userHost, remoteCommand)
+	if !quiet {
+		return executeCommand("", "ssh", sshArgs...)
+	}
+	_, err := captureCommand("", "ssh", sshArgs...)
+	return err
+}
+
+// afterTransferHookCommand builds the remote command line for
+// --after-transfer-hook. Any "{path}" in hook is replaced with the quoted
+// remote repository path, which is also exported as TERYX_REMOTE_PATH.
+func afterTransferHookCommand(hook, remoteRepoPath string) string {
+	quotedPath := remoteShellPath(remoteRepoPath)
+	return fmt.Sprintf("TERYX_REMOTE_PATH=%s; export TERYX_REMOTE_PATH; %s",
+		quotedPath, strings.ReplaceAll(hook, "{path}", quotedPath))
+}
+
+// transferFile copies one repository file to destination (user@host:path)
+// with scp, falling back to an sftp 'put' into remotePath if scp fails. With
+// preserve, both keep the file's modification time and mode.
+func transferFile(repoName, destination, userHost, remotePath string, sshOpts []string, preserve bool) error {
+	// 1. Try scp first. scp draws its own progress meter unless told to be quiet.
+	scpArgs := append([]string{}, sshOpts...)
+	if quiet {
+		scpArgs = append(scpArgs, "-q")
+	}
+	if preserve {
+		scpArgs = append(scpArgs, "-p")
+	}
+	scpArgs = append(scpArgs, repoName, destination)
+	err := executeCommand("", "scp", scpArgs...)
+	if err == nil {
+		return nil
+	}
+	fmt.Printf("⚠️ scp failed: %v\n", err)
+	fmt.Println("ℹ️ Falling back to sftp...")
+
+	// 2. Fallback to sftp
+	// Construct the sftp command to run non-interactively
+	// This approach pipes the 'put' command into sftp's standard input.
+	putCommand := "put"
+	if preserve {
+		putCommand = "put -p"
+	}
+	sftpCommand := fmt.Sprintf("%s %s %s", putCommand, repoName, remotePath)
+	// sftp shows a progress meter when attached to a terminal; -q turns it off.
+	sftpArgs := append([]string{}, sshOpts...)
+	if quiet {
+		sftpArgs = append(sftpArgs, "-q")
+	}
+	sftpArgs = append(sftpArgs, userHost)
+	sftpCmd := exec.Command("sftp", sftpArgs...)
+	sftpCmd.Stdin = strings.NewReader(sftpCommand)
+	sftpCmd.Stdout = os.Stdout
+	sftpCmd.Stderr = os.Stderr
+
+	printBanner(os.Stdout, sftpCmd)
+
+	if err := sftpCmd.Run(); err != nil {
+		return fmt.Errorf("sftp fallback also failed: %w", err)
+	}
+	return nil
+}
+
+// validateGlobs checks that each --include/--exclude pattern is a valid
+// filepath.Match pattern.
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// selectRepoFiles returns the .fossil files directly inside dir that pass the
+// include and exclude globs, which are matched against the base names. With no
+// include patterns every file is included; an exclude match always wins. Each
+// skipped file is reported along with the reason.
+func selectRepoFiles(dir string, include, exclude []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".fossil") {
+			continue
+		}
+		if _, ok := firstMatchingGlob(include, name); len(include) > 0 && !ok {
+			fmt.Printf("⏭️  Skipping %s: matches no --include pattern\n", name)
+			continue
+		}
+		if pattern, ok := firstMatchingGlob(exclude, name); ok {
+			fmt.Printf("⏭️  Skipping %s: matches --exclude '%s'\n", name, pattern)
+			continue
+		}
+		selected = append(selected, filepath.Join(dir, name))
+	}
+	return selected, nil
+}
+
+// firstMatchingGlob returns the first of the patterns that name matches.
+// Patterns are assumed to have been checked with validateGlobs.
+func firstMatchingGlob(patterns []string, name string) (string, bool) {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// fixPermsCommand builds the remote shell command that hands the repository
+// files at repoPaths to owner (as user and group) with fileMode, so the web
+// server can write to them. With a dirMode, the directories holding the files
+// are given to owner with that mode as well, since SQLite creates its journal
+// files next to the repository.
+func fixPermsCommand(owner, fileMode, dirMode string, repoPaths ...string) string {
+	var quotedFiles, quotedDirs []string
+	for _, repoPath := range repoPaths {
+		quotedFiles = append(quotedFiles, remoteShellPath(repoPath))
+		if dir := remoteShellPath(path.Dir(repoPath)); dirMode != "" && !slices.Contains(quotedDirs, dir) {
+			quotedDirs = append(quotedDirs, dir)
+		}
+	}
+	ownership := shellQuote(owner + ":" + owner)
+	files := strings.Join(quotedFiles, " ")
+	command := fmt.Sprintf("sudo chown %s %s && sudo chmod %s %s", ownership, files, shellQuote(fileMode), files)
+	if len(quotedDirs) > 0 {
+		dirs := strings.Join(quotedDirs, " ")
+		command += fmt.Sprintf(" && sudo chown %s %s && sudo chmod %s %s", ownership, dirs, shellQuote(dirMode), dirs)
+	}
+	return command
+}
@@ -0,0 +1,120 @@
+package main
+
+import "testing"
+
+func TestSplitDestination(t *testing.T) {
+	tests := []struct {
+		name         string
+		destination  string
+		wantUserHost string
+		wantPath     string
+		wantErr      bool
+	}{
+		{"directory with trailing slash", "user@host:/srv/repos/", "user@host", "/srv/repos/", false},
+		{"explicit file name", "host:/srv/repos/x.fossil", "host", "/srv/repos/x.fossil", false},
+		{"empty path is the home directory", "user@host:", "user@host", "", false},
+		{"missing colon", "user@host", "", "", true},
+		{"missing host", ":/srv/repos/", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userHost, remotePath, err := splitDestination(tt.destination)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitDestination(%q) error = %v, want error %v", tt.destination, err, tt.wantErr)
+			}
+			if userHost != tt.wantUserHost || remotePath != tt.wantPath {
+				t.Errorf("splitDestination(%q) = %q, %q, want %q, %q", tt.destination, userHost, remotePath, tt.wantUserHost, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestRemoteRepoPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		remotePath string
+		repoName   string
+		want       string
+	}{
+		{"directory with trailing slash", "/srv/repos/", "x.fossil", "/srv/repos/x.fossil"},
+		{"directory without trailing slash", "/srv/repos", "x.fossil", "/srv/repos/x.fossil"},
+		{"explicit file name", "/srv/repos/y.fossil", "x.fossil", "/srv/repos/y.fossil"},
+		{"empty path is the home directory", "", "x.fossil", "x.fossil"},
+		{"local directory is dropped", "/srv/repos/", "fossils/x.fossil", "/srv/repos/x.fossil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remoteRepoPath(tt.remotePath, tt.repoName); got != tt.want {
+				t.Errorf("remoteRepoPath(%q, %q) = %q, want %q", tt.remotePath, tt.repoName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain word", "repo.fossil", "'repo.fossil'"},
+		{"space", "/srv/my repos", "'/srv/my repos'"},
+		{"single quote", "it's", `'it'\''s'`},
+		{"empty", "", "''"},
+		{"tilde is quoted", "~/fossils", "'~/fossils'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shellQuote(tt.in); got != tt.want {
+				t.Errorf("shellQuote(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteShellPath(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"absolute path", "/srv/repos/x.fossil", "'/srv/repos/x.fossil'"},
+		{"home-relative path", "~/fossils/x.fossil", "~/'fossils/x.fossil'"},
+		{"home-relative path with a space", "~/my fossils/x.fossil", "~/'my fossils/x.fossil'"},
+		{"home directory", "~", "~"},
+		{"home directory with slash", "~/", "~/"},
+		{"other user's home stays quoted", "~bob/x.fossil", "'~bob/x.fossil'"},
+		{"tilde inside the path stays quoted", "/srv/~/x.fossil", "'/srv/~/x.fossil'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := remoteShellPath(tt.in); got != tt.want {
+				t.Errorf("remoteShellPath(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixPermsCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		dirMode   string
+		repoPaths []string
+		want      string
+	}{
+		{"one file", "", []string{"/srv/repos/x.fossil"},
+			"sudo chown 'www-data:www-data' '/srv/repos/x.fossil' && sudo chmod '664' '/srv/repos/x.fossil'"},
+		{"home-relative file", "", []string{"~/fossils/x.fossil"},
+			"sudo chown 'www-data:www-data' ~/'fossils/x.fossil' && sudo chmod '664' ~/'fossils/x.fossil'"},
+		{"shared directory listed once", "775", []string{"~/fossils/x.fossil", "~/fossils/y.fossil"},
+			"sudo chown 'www-data:www-data' ~/'fossils/x.fossil' ~/'fossils/y.fossil' && sudo chmod '664' ~/'fossils/x.fossil' ~/'fossils/y.fossil'" +
+				" && sudo chown 'www-data:www-data' ~/'fossils' && sudo chmod '775' ~/'fossils'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixPermsCommand("www-data", "664", tt.dirMode, tt.repoPaths...); got != tt.want {
+				t.Errorf("fixPermsCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// ui.go
+//
+// Implements 'teryx ui', which opens a repository's web interface locally,
+// optionally as seen by a particular user rather than the local admin.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// uiCmd handles the 'teryx ui' command.
+var uiCmd = &cobra.Command{
+	Use:   "ui [repository-file]",
+	Short: "Opens the web interface, optionally as a specific user.",
+	Long: `Runs 'fossil ui' for the open checkout, or the repository file given as an
+argument or via -R, on --port, and opens it in the browser.
+
+'fossil ui' normally logs you in as the local admin automatically, which hides
+what other users can see. --as-user turns that off (fossil's --nobody option),
+so pages are shown with the capabilities of the special 'nobody' user, and opens
+the login page with the given user filled in; log in with their password to
+test their capabilities. 'nobody' itself needs no login, and 'anonymous' gets
+the anonymous login page.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		port, _ := cmd.Flags().GetInt("port")
+		asUser, _ := cmd.Flags().GetString("as-user")
+		repoArgs := repositoryArgs(cmd, args)
+
+		fossilArgs := []string{"ui", "--port", strconv.Itoa(port)}
+		page := ""
+		if asUser != "" {
+			fossilArgs = append(fossilArgs, "--nobody")
+			switch asUser {
+			case "nobody":
+			case "anonymous":
+				page = "login?anon"
+			default:
+				users, err := listRepoUsers(repoArgs)
+				if err != nil {
+					log.Fatalf("❌ Failed to list repository users: %v", err)
+				}
+				if !slices.Contains(users, asUser) {
+					log.Fatalf("❌ '%s' is not a user of this repository.", asUser)
+				}
+				page = "login?u=" + url.QueryEscape(asUser)
+			}
+			if page != "" {
+				fossilArgs = append(fossilArgs, "--page", page)
+			}
+		}
+		if repoArgs != nil {
+			fossilArgs = append(fossilArgs, repoArgs[1])
+		}
+
+		fmt.Printf("🌐 Open http://localhost:%d/%s (press Ctrl-C to stop)\n", port, page)
+		if asUser != "" {
+			fmt.Printf("ℹ️  Automatic admin login is off; the UI shows what '%s' can see once logged in.\n", asUser)
+		}
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ fossil ui stopped: %v", err)
+		}
+	},
+}
+
+func init() {
+	addRepositoryFlag(uiCmd)
+	uiCmd.Flags().Int("port", 8080, "Port to serve the web interface on")
+	uiCmd.Flags().String("as-user", "", "Show the UI as this user instead of logging in as the local admin")
+
+	rootCmd.AddCommand(uiCmd)
+}
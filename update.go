@@ -0,0 +1,119 @@
+// update.go
+//
+// Implements 'teryx update' and 'teryx merge', wrappers around the fossil
+// commands of the same name that confirm before crossing onto another branch.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// currentBranch returns the branch of the open checkout.
+func currentBranch() (string, error) {
+	output, err := captureCommand("", "fossil", "branch", "current")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// checkinBranch returns the branch that a branch name, tag or check-in hash
+// resolves to, taken from the matching timeline entry.
+func checkinBranch(version string) (string, error) {
+	entries, err := fetchTimeline("before", version, "-n", "1")
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no check-in found for '%s'", version)
+	}
+	return entries[0].Branch, nil
+}
+
+// branchGuardPreRun is the PreRunE hook of 'teryx update' and 'teryx merge'.
+// When the target in args[0] is on a different branch than the checkout, it
+// shows both branches and, on a terminal, asks before continuing. --yes skips
+// the question.
+func branchGuardPreRun(cmd *cobra.Command, args []string) error {
+	if err := checkoutPreRun(cmd, args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	current, err := currentBranch()
+	if err != nil {
+		return fmt.Errorf("❌ Could not determine the current branch: %v", err)
+	}
+	target, err := checkinBranch(args[0])
+	if err != nil {
+		return fmt.Errorf("❌ Could not determine the branch of '%s': %v", args[0], err)
+	}
+	if target == current {
+		return nil
+	}
+
+	fmt.Printf("⚠️ The checkout is on branch '%s', but '%s' is on branch '%s'.\n", current, args[0], target)
+	if yes, _ := cmd.Flags().GetBool("yes"); yes || !stdinIsTerminal() {
+		return nil
+	}
+	if !confirm(fmt.Sprintf("Continue with %s from '%s' to '%s'?", cmd.Name(), current, target)) {
+		return errors.New("ℹ️  Aborted; the checkout was not changed.")
+	}
+	return nil
+}
+
+// updateCmd handles the 'teryx update' command.
+var updateCmd = &cobra.Command{
+	Use:   "update [version]",
+	Short: "Updates the open checkout, confirming before switching branches.",
+	Long: `Wraps 'fossil update' for the open checkout. Without a version, the checkout is
+brought up to date with the latest check-in on its branch. If the given version
+is on a different branch, both branches are shown and, when run interactively,
+you are asked to confirm the switch; --yes skips the question.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: branchGuardPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executeCommand("", "fossil", append([]string{"update"}, args...)...); err != nil {
+			log.Fatalf("❌ Failed to update: %v", err)
+		}
+
+		fmt.Println("✅ Success! Checkout updated.")
+	},
+}
+
+// mergeCmd handles the 'teryx merge' command.
+var mergeCmd = &cobra.Command{
+	Use:   "merge <version>",
+	Short: "Merges a version into the open checkout, confirming across branches.",
+	Long: `Wraps 'fossil merge' for the open checkout. If the version being merged is on
+a different branch, both branches are shown and, when run interactively, you
+are asked to confirm; --yes skips the question. The merge is left uncommitted,
+as with fossil.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: branchGuardPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executeCommand("", "fossil", "merge", args[0]); err != nil {
+			log.Fatalf("❌ Failed to merge: %v", err)
+		}
+
+		fmt.Println("✅ Success! Merge applied; review it and run 'teryx commit'.")
+	},
+}
+
+func init() {
+	updateCmd.Flags().Bool("yes", false, "Switch branches without asking for confirmation")
+	mergeCmd.Flags().Bool("yes", false, "Merge across branches without asking for confirmation")
+
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,132 @@
+// users.go
+//
+// Implements 'teryx export-users' and 'teryx import-users', which copy the user
+// and capability table between repositories via 'fossil configuration'.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// listRepoUsers returns the login names of all users in a repository, using
+// 'fossil user list' with the given repository arguments (see repositoryArgs).
+func listRepoUsers(repoArgs []string) ([]string, error) {
+	output, err := captureCommand("", "fossil", append([]string{"user", "list"}, repoArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			users = append(users, fields[0])
+		}
+	}
+	return users, nil
+}
+
+// exportedUserLogins returns the login names contained in a file written by
+// 'fossil configuration export user'. Each user record is introduced by a
+// "config /user <length>" line and followed by "<mtime> '<login>' ...".
+func exportedUserLogins(content string) []string {
+	var logins []string
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "config /user ") || i+1 >= len(lines) {
+			continue
+		}
+		fields := strings.Fields(lines[i+1])
+		if len(fields) >= 2 {
+			logins = append(logins, strings.Trim(fields[1], "'"))
+		}
+	}
+	return logins
+}
+
+// exportUsersCmd handles the 'teryx export-users' command.
+var exportUsersCmd = &cobra.Command{
+	Use:   "export-users [repository-file]",
+	Short: "Exports a repository's users and capabilities to a file.",
+	Long: `Writes the user table of the open checkout's repository, or of the given
+repository file, to a file with 'fossil configuration export user'. The
+result can be applied to another repository with 'teryx import-users'.`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: checkoutOrRepositoryPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		fossilArgs := append([]string{"configuration", "export", "user", outputFile}, repositoryArgs(cmd, args)...)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to export users: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Users exported to %s\n", outputFile)
+	},
+}
+
+// importUsersCmd handles the 'teryx import-users' command.
+var importUsersCmd = &cobra.Command{
+	Use:   "import-users <file> [repository-file]",
+	Short: "Applies users and capabilities exported by 'teryx export-users'.",
+	Long: `Imports a user table written by 'teryx export-users' into the open checkout's
+repository, or into the given repository file. Users in the file are added,
+or updated if they already exist. Use --dry-run to see which.`,
+	Args: cobra.RangeArgs(1, 2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkoutOrRepositoryPreRun(cmd, args[1:])
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		usersFile := args[0]
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		repoArgs := repositoryArgs(cmd, args[1:])
+
+		content, err := os.ReadFile(usersFile)
+		if err != nil {
+			log.Fatalf("❌ Cannot read users file: %v", err)
+		}
+		logins := exportedUserLogins(string(content))
+		if len(logins) == 0 {
+			log.Fatalf("❌ No users found in '%s'. Was it written by 'teryx export-users'?", usersFile)
+		}
+
+		if dryRun {
+			existing, err := listRepoUsers(repoArgs)
+			if err != nil {
+				log.Fatalf("❌ Failed to list current users: %v", err)
+			}
+			fmt.Printf("ℹ️  Dry run: importing '%s' would make these changes:\n", usersFile)
+			for _, login := range logins {
+				if slices.Contains(existing, login) {
+					fmt.Printf("  ~ update %s\n", login)
+				} else {
+					fmt.Printf("  + add    %s\n", login)
+				}
+			}
+			return
+		}
+
+		fossilArgs := append([]string{"configuration", "import", usersFile}, repoArgs...)
+		if err := executeCommand("", "fossil", fossilArgs...); err != nil {
+			log.Fatalf("❌ Failed to import users: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Imported %d users.\n", len(logins))
+	},
+}
+
+func init() {
+	addRepositoryFlag(exportUsersCmd)
+	exportUsersCmd.Flags().StringP("output", "o", "users.conf", "File to write the exported users to")
+
+	addRepositoryFlag(importUsersCmd)
+	importUsersCmd.Flags().Bool("dry-run", false, "Show which users would be added or updated without changing anything")
+
+	rootCmd.AddCommand(exportUsersCmd)
+	rootCmd.AddCommand(importUsersCmd)
+}
@@ -0,0 +1,139 @@
+// verifyconfig.go
+//
+// Implements 'teryx verify-config', which checks the configuration file more
+// strictly than loading it does, so mistakes show up before they cause odd
+// behavior.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// configKeys are the keys the configuration file may contain.
+var configKeys = []string{"base-dir", "layout", "repos"}
+
+// configListKeys are the keys whose value is a list of "- item" lines.
+var configListKeys = []string{"repos"}
+
+// configIssues reports everything wrong with the parsed entries of a
+// configuration file: unknown or repeated keys (which loadConfig ignores or
+// lets the last one win), values of the wrong kind, and invalid values.
+func configIssues(entries []configEntry) []*configLineError {
+	var issues []*configLineError
+	report := func(line int, format string, args ...any) {
+		issues = append(issues, &configLineError{line, fmt.Errorf(format, args...)})
+	}
+
+	seen := make(map[string]int)
+	for _, entry := range entries {
+		if !slices.Contains(configKeys, entry.Key) {
+			normalized := strings.ReplaceAll(strings.ToLower(entry.Key), "_", "-")
+			if slices.Contains(configKeys, normalized) {
+				report(entry.Line, "unknown key '%s'; did you mean '%s'?", entry.Key, normalized)
+			} else {
+				report(entry.Line, "unknown key '%s'; known keys are %s", entry.Key, strings.Join(configKeys, ", "))
+			}
+			continue
+		}
+		if first, ok := seen[entry.Key]; ok {
+			report(entry.Line, "'%s' is already set on line %d; only the last one takes effect", entry.Key, first)
+		}
+		seen[entry.Key] = entry.Line
+
+		isList := slices.Contains(configListKeys, entry.Key)
+		switch {
+		case isList && entry.Value != "":
+			report(entry.Line, "%s must be a list of '- <value>' lines, not a value", entry.Key)
+			continue
+		case !isList && len(entry.List) > 0:
+			report(entry.Line, "%s takes a single value, not a list", entry.Key)
+			continue
+		case !isList && entry.Value == "":
+			report(entry.Line, "%s has no value", entry.Key)
+			continue
+		}
+
+		switch entry.Key {
+		case "base-dir":
+			baseDir, err := expandHome(entry.Value)
+			if err != nil {
+				report(entry.Line, "%v", err)
+			} else if info, err := os.Stat(baseDir); err == nil && !info.IsDir() {
+				report(entry.Line, "base-dir %s is not a directory", baseDir)
+			}
+		case "layout":
+			if entry.Value != "nested" && entry.Value != "flat" {
+				report(entry.Line, "layout must be 'nested' or 'flat', not '%s'", entry.Value)
+			}
+		case "repos":
+			if len(entry.List) == 0 {
+				report(entry.Line, "repos has no '- <url>' items")
+			}
+			for i, item := range entry.List {
+				if _, err := normalizeRemoteURL(item); err != nil {
+					report(entry.Line, "repos item %d: %v", i+1, err)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// verifyConfigCmd handles the 'teryx verify-config' command.
+var verifyConfigCmd = &cobra.Command{
+	Use:   "verify-config",
+	Short: "Checks the config file for unknown keys and invalid values.",
+	Long: `Reads the config file (~/.config/teryx/config) and reports, with line
+numbers, every problem found: lines that can't be parsed, unknown keys (which
+the other commands silently ignore), keys set twice, lists where a value is
+expected and vice versa, an invalid layout, a base-dir that isn't a directory,
+and repos entries that aren't valid URLs. The exit status is non-zero if
+anything is wrong. A missing config file is fine.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := configPath()
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("ℹ️  There is no config file at %s; the defaults apply.\n", path)
+			return
+		} else if err != nil {
+			log.Fatalf("❌ Could not read %s: %v", path, err)
+		}
+
+		var issues []*configLineError
+		entries, err := parseConfig(string(content))
+		var lineErr *configLineError
+		if errors.As(err, &lineErr) {
+			// Parsing stops at the first malformed line, so report only that.
+			issues = append(issues, lineErr)
+		} else if err != nil {
+			log.Fatalf("❌ %s: %v", path, err)
+		} else {
+			issues = configIssues(entries)
+		}
+
+		if len(issues) == 0 {
+			fmt.Printf("✅ %s is valid.\n", path)
+			return
+		}
+		for _, issue := range issues {
+			fmt.Printf("⚠️ %s:%d: %v\n", path, issue.Line, issue.Err)
+		}
+		log.Fatalf("❌ %d problem(s) found in %s.", len(issues), path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyConfigCmd)
+}
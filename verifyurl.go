@@ -0,0 +1,71 @@
+// verifyurl.go
+//
+// Implements 'teryx verify-url', which shows how 'teryx clone' would interpret
+// a repository URL without contacting the server.
+
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyURLCmd handles the 'teryx verify-url' command.
+var verifyURLCmd = &cobra.Command{
+	Use:   "verify-url <fossil-url>",
+	Short: "Shows how 'teryx clone' would interpret a URL, without cloning.",
+	Long: `Prints what 'teryx clone' would do with a URL: the cleaned URL (a trailing
+/home from the web UI is dropped), the user name it adds, the URL fossil would
+be given, and the local directory, repository file and checkout it would
+create. Nothing is contacted or created.
+
+--anonymous, --keep-url and --flat have the same effect as for 'teryx clone'.
+A password in the URL is shown redacted. --json prints the same as an object.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: rejectFlagConflicts(nil, [2]string{"keep-url", "anonymous"}),
+	Run: func(cmd *cobra.Command, args []string) {
+		anonymous, _ := cmd.Flags().GetBool("anonymous")
+		keepURL, _ := cmd.Flags().GetBool("keep-url")
+		flat, _ := cmd.Flags().GetBool("flat")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		plan, err := planClone(args[0], "", anonymous, keepURL, flat)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		plan.URL = redactURL(plan.URL)
+		plan.CleanURL = redactURL(plan.CleanURL)
+		plan.CloneURL = redactURL(plan.CloneURL)
+
+		if asJSON {
+			if err := printJSON(plan); err != nil {
+				log.Fatalf("❌ Failed to write JSON: %v", err)
+			}
+			return
+		}
+
+		username := plan.Username
+		if username == "" {
+			username = "(none)"
+		}
+		fmt.Printf("Input URL:     %s\n", plan.URL)
+		fmt.Printf("Cleaned URL:   %s\n", plan.CleanURL)
+		fmt.Printf("Username:      %s\n", username)
+		fmt.Printf("Clone URL:     %s\n", plan.CloneURL)
+		fmt.Printf("Layout:        %s\n", plan.Layout)
+		fmt.Printf("Target dir:    %s\n", plan.TargetDir)
+		fmt.Printf("Repo file:     %s\n", plan.RepoFile)
+		fmt.Printf("Checkout dir:  %s\n", plan.CheckoutDir)
+	},
+}
+
+func init() {
+	verifyURLCmd.Flags().Bool("anonymous", false, "Show the result of cloning anonymously, without a username")
+	verifyURLCmd.Flags().Bool("keep-url", false, "Show the result of passing the URL to fossil exactly as given")
+	verifyURLCmd.Flags().Bool("flat", false, "Show the result of using the flat layout")
+	verifyURLCmd.Flags().Bool("json", false, "Print the result as JSON")
+
+	rootCmd.AddCommand(verifyURLCmd)
+}
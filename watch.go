@@ -0,0 +1,177 @@
+// watch.go
+//
+// Implements 'teryx watch', which commits changes in the open checkout
+// automatically once they settle, e.g. for notes kept in a repository.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchIgnoredNames are the checkout's own metadata files, whose changes
+// must not trigger a commit.
+var watchIgnoredNames = []string{".fslckout", "_FOSSIL_", ".fslckout-journal", ".fslckout-wal", ".fslckout-shm"}
+
+// isWatchMetadata reports whether path is a fossil metadata file of the
+// checkout, or the repository file itself or one of its journals.
+func isWatchMetadata(path, repoPath string) bool {
+	for _, name := range watchIgnoredNames {
+		if filepath.Base(path) == name {
+			return true
+		}
+	}
+	return repoPath != "" && strings.HasPrefix(path, repoPath)
+}
+
+// addWatchTree adds dir and every directory below it to the watcher.
+func addWatchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// watchMessage fills in the --message template: {date} becomes the current
+// time and {count} the number of changed files.
+func watchMessage(template string, count int) string {
+	return strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02 15:04:05"),
+		"{count}", strconv.Itoa(count),
+	).Replace(template)
+}
+
+// autoCommit adds new files and removes deleted ones via 'fossil addremove',
+// which honors ignore-glob, and commits them through 'teryx commit' if there
+// is anything to commit.
+func autoCommit(root, template string, push bool) error {
+	if _, err := captureCommand(root, "fossil", "addremove"); err != nil {
+		return err
+	}
+	changes, err := pendingChanges(root)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	commitArgs := []string{"commit", "-m", watchMessage(template, len(changes)), "--no-sync"}
+	if push {
+		commitArgs[len(commitArgs)-1] = "--sync"
+	}
+	commit := exec.Command(self, append(globalFlagArgs(), commitArgs...)...)
+	commit.Dir = root
+	commit.Stdout = os.Stdout
+	commit.Stderr = os.Stderr
+	return commit.Run()
+}
+
+// watchCmd handles the 'teryx watch' command.
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Commits changes in the open checkout automatically as they happen.",
+	Long: `Watches the open checkout for file changes and, once no further change has
+happened for --interval, adds new files, removes deleted ones and commits
+everything with 'teryx commit'. Files matching the ignore-glob setting are never
+added, and fossil's own metadata files are not watched. Runs until interrupted.
+
+--message is a template for the check-in comment, in which {date} is replaced
+with the current time and {count} with the number of changed files. Without
+--push, the commits are local; with it, each one is synced to the remote.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		template, _ := cmd.Flags().GetString("message")
+		push, _ := cmd.Flags().GetBool("push")
+		if interval <= 0 {
+			log.Fatal("❌ --interval must be positive.")
+		}
+
+		root, err := fossilInfoField("", "local-root")
+		if err != nil {
+			log.Fatalf("❌ Could not determine the checkout root: %v", err)
+		}
+		repoPath, err := checkoutRepository("")
+		if err != nil {
+			log.Fatalf("❌ Could not determine the checkout's repository: %v", err)
+		}
+
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			log.Fatalf("❌ Failed to start watching: %v", err)
+		}
+		defer watcher.Close()
+		if err := addWatchTree(watcher, root); err != nil {
+			log.Fatalf("❌ Failed to watch %s: %v", root, err)
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+
+		fmt.Printf("🚀 Watching %s; changes are committed after %s of quiet. Press Ctrl-C to stop.\n", root, interval)
+		debounce := time.NewTimer(interval)
+		debounce.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if isWatchMetadata(event.Name, repoPath) {
+					continue
+				}
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						if err := addWatchTree(watcher, event.Name); err != nil {
+							fmt.Printf("⚠️ Could not watch new directory %s: %v\n", event.Name, err)
+						}
+					}
+				}
+				debounce.Reset(interval)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				fmt.Printf("⚠️ Watch error: %v\n", err)
+			case <-debounce.C:
+				if err := autoCommit(root, template, push); err != nil {
+					fmt.Printf("⚠️ Auto-commit failed; will retry on the next change: %v\n", err)
+				}
+			case <-interrupt:
+				fmt.Println("ℹ️  Stopped watching. Any changes since the last commit are left uncommitted.")
+				return
+			}
+		}
+	},
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", 5*time.Second, "How long changes must settle before they are committed")
+	watchCmd.Flags().StringP("message", "m", "Auto-commit at {date} ({count} files)", "Check-in comment template; {date} and {count} are filled in")
+	watchCmd.Flags().Bool("push", false, "Sync each commit to the remote")
+
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,97 @@
+// whatsnew.go
+//
+// Implements 'teryx whatsnew', which lists what was committed since a release
+// tag, e.g. to write release notes before deploying.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// latestReleaseTag returns the most recently applied tag whose name starts
+// with "release", read from the repository's tag tables since 'fossil tag
+// list' doesn't show when tags were added.
+func latestReleaseTag() (string, error) {
+	query := "SELECT substr(tag.tagname, 5) FROM tagxref" +
+		" JOIN tag ON tag.tagid = tagxref.tagid" +
+		" WHERE tag.tagname GLOB 'sym-release*' AND tagxref.tagtype > 0" +
+		" ORDER BY tagxref.mtime DESC LIMIT 1"
+	output, err := captureCommand("", "fossil", "sql", "--readonly", query)
+	if err != nil {
+		return "", err
+	}
+	tag := strings.TrimSpace(string(output))
+	if tag == "" {
+		return "", fmt.Errorf("no tag starting with 'release' found; pass --since-tag")
+	}
+	return tag, nil
+}
+
+// whatsnewCmd handles the 'teryx whatsnew' command.
+var whatsnewCmd = &cobra.Command{
+	Use:   "whatsnew",
+	Short: "Lists the check-ins made since a release tag.",
+	Long: `Lists the check-ins that descend from the check-in tagged --since-tag, oldest
+first, which by default is the most recently added tag whose name starts with
+"release" (e.g. release-1.2). With --stat, a diffstat between the tag and the
+tip follows. The output is meant as a starting point for release notes.`,
+	Args:    cobra.NoArgs,
+	PreRunE: checkoutPreRun,
+	Run: func(cmd *cobra.Command, args []string) {
+		tag, _ := cmd.Flags().GetString("since-tag")
+		stat, _ := cmd.Flags().GetBool("stat")
+
+		if tag == "" {
+			var err error
+			if tag, err = latestReleaseTag(); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+		tagged, err := fossilInfoField("", "hash", tag)
+		if err != nil {
+			log.Fatalf("❌ Could not find the check-in tagged '%s': %v", tag, err)
+		}
+		tagged, _, _ = strings.Cut(tagged, " ")
+
+		entries, err := fetchTimeline("descendants", tag, "-n", "0")
+		if err != nil {
+			log.Fatalf("❌ Failed to read the timeline: %v", err)
+		}
+		var news []timelineEntry
+		for i := len(entries) - 1; i >= 0; i-- {
+			if entries[i].Hash != tagged {
+				news = append(news, entries[i])
+			}
+		}
+
+		if len(news) == 0 {
+			fmt.Printf("ℹ️  Nothing new since '%s'.\n", tag)
+			return
+		}
+		fmt.Printf("%d check-ins since '%s':\n\n", len(news), tag)
+		for _, entry := range news {
+			fmt.Printf("- %s (%.10s, %s, %s)\n", entry.Comment, entry.Hash, entry.Author, entry.Date)
+		}
+
+		if stat {
+			output, err := captureCommand("", "fossil", append([]string{"diff", "--from", tag, "--to", "tip"}, internalDiffArgs...)...)
+			if err != nil {
+				log.Fatalf("❌ Failed to compute diff: %v", err)
+			}
+			fmt.Println()
+			printDiffStat(parseDiffStat(string(output)))
+		}
+	},
+}
+
+func init() {
+	whatsnewCmd.Flags().String("since-tag", "", "Tag to list changes since (default: the latest tag starting with 'release')")
+	whatsnewCmd.Flags().Bool("stat", false, "Also show a diffstat between the tag and the tip")
+
+	rootCmd.AddCommand(whatsnewCmd)
+}
@@ -0,0 +1,90 @@
+// wiki.go
+//
+// Implements the 'teryx wiki' command group, a wrapper around 'fossil wiki'
+// for listing, exporting and committing wiki pages in the open checkout.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// wikiCmd is the parent of the 'teryx wiki' subcommands.
+var wikiCmd = &cobra.Command{
+	Use:               "wiki",
+	Short:             "Lists, exports and commits wiki pages in the open checkout's repository.",
+	PersistentPreRunE: checkoutPreRun,
+}
+
+// wikiListCmd handles the 'teryx wiki list' command.
+var wikiListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all wiki pages.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := executeCommand("", "fossil", "wiki", "list"); err != nil {
+			log.Fatalf("❌ Failed to list wiki pages: %v", err)
+		}
+	},
+}
+
+// wikiExportCmd handles the 'teryx wiki export' command.
+var wikiExportCmd = &cobra.Command{
+	Use:   "export <page>",
+	Short: "Writes the current content of a wiki page to stdout or a file.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		page := args[0]
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		if outputFile != "" {
+			if err := executeCommand("", "fossil", "wiki", "export", page, outputFile); err != nil {
+				log.Fatalf("❌ Failed to export wiki page: %v", err)
+			}
+			fmt.Printf("✅ Success! Wiki page '%s' written to %s\n", page, outputFile)
+			return
+		}
+
+		// Capture the page so only its content, not teryx's banner, lands on stdout.
+		content, err := captureCommand("", "fossil", "wiki", "export", page)
+		if err != nil {
+			log.Fatalf("❌ Failed to export wiki page: %v", err)
+		}
+		os.Stdout.Write(content)
+	},
+}
+
+// wikiCommitCmd handles the 'teryx wiki commit' command.
+var wikiCommitCmd = &cobra.Command{
+	Use:   "commit <page> <file>",
+	Short: "Replaces the content of an existing wiki page with a file.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		page := args[0]
+		file := args[1]
+
+		if _, err := os.Stat(file); err != nil {
+			log.Fatalf("❌ Cannot read '%s': %v", file, err)
+		}
+
+		fmt.Printf("🚀 Committing '%s' to wiki page '%s'...\n", file, page)
+		if err := executeCommand("", "fossil", "wiki", "commit", page, file); err != nil {
+			log.Fatalf("❌ Failed to commit wiki page: %v", err)
+		}
+
+		fmt.Printf("✅ Success! Wiki page '%s' updated.\n", page)
+	},
+}
+
+func init() {
+	wikiExportCmd.Flags().StringP("output", "o", "", "Write the page to this file instead of stdout")
+
+	wikiCmd.AddCommand(wikiListCmd)
+	wikiCmd.AddCommand(wikiExportCmd)
+	wikiCmd.AddCommand(wikiCommitCmd)
+	rootCmd.AddCommand(wikiCmd)
+}
@@ -0,0 +1,159 @@
+// worddiff.go
+//
+// Implements 'teryx diff --word-diff', which rewrites the line-based unified
+// diff of 'fossil diff' to show changes word by word, as 'git diff
+// --word-diff' does. Fossil has no word diff of its own.
+
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wordTokenPattern splits text into the units a word diff compares: runs of
+// non-whitespace, runs of blanks, and single newlines.
+var wordTokenPattern = regexp.MustCompile(`[^\s]+|[ \t\r\f\v]+|\n`)
+
+// maxWordDiffCells caps the size of the table diffWords fills for one block
+// of changed lines. Larger blocks are shown as wholly removed and added.
+const maxWordDiffCells = 4_000_000
+
+// wordOp is one step of a word diff: a token kept, removed ('-') or added
+// ('+').
+type wordOp struct {
+	Kind  byte
+	Token string
+}
+
+// diffWords returns the steps turning the tokens of before into those of
+// after, using a longest common subsequence so as many tokens as possible are
+// kept.
+func diffWords(before, after []string) []wordOp {
+	var ops []wordOp
+	if len(before)*len(after) > maxWordDiffCells {
+		for _, token := range before {
+			ops = append(ops, wordOp{'-', token})
+		}
+		for _, token := range after {
+			ops = append(ops, wordOp{'+', token})
+		}
+		return ops
+	}
+
+	// common[i][j] is the length of the longest common subsequence of
+	// before[i:] and after[j:].
+	common := make([][]int, len(before)+1)
+	for i := range common {
+		common[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			ops = append(ops, wordOp{' ', before[i]})
+			i++
+			j++
+		// Removals go first on ties, so they read [-old-]{+new+}.
+		case i < len(before) && (j == len(after) || common[i+1][j] >= common[i][j+1]):
+			ops = append(ops, wordOp{'-', before[i]})
+			i++
+		default:
+			ops = append(ops, wordOp{'+', after[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeWordOps renders ops in git's plain word diff format, with removed text
+// in [-...-] and added text in {+...+}. Markers never span a line break, so
+// every output line stands on its own.
+func writeWordOps(out *strings.Builder, ops []wordOp) {
+	openMarker := map[byte]string{'-': "[-", '+': "{+"}
+	closeMarker := map[byte]string{'-': "-]", '+': "+}"}
+	for start := 0; start < len(ops); {
+		end := start
+		for end < len(ops) && ops[end].Kind == ops[start].Kind {
+			end++
+		}
+		var run strings.Builder
+		for _, op := range ops[start:end] {
+			run.WriteString(op.Token)
+		}
+		kind := ops[start].Kind
+		if kind == ' ' {
+			out.WriteString(run.String())
+		} else {
+			for i, segment := range strings.Split(run.String(), "\n") {
+				if i > 0 {
+					out.WriteString("\n")
+				}
+				if segment != "" {
+					out.WriteString(openMarker[kind] + segment + closeMarker[kind])
+				}
+			}
+		}
+		start = end
+	}
+}
+
+// wordDiff rewrites a unified diff, as produced by 'fossil diff' with
+// internalDiffArgs, into a word diff. File and hunk headers are kept; within
+// hunks, each block of removed lines and the added lines following it are
+// compared word by word, and context lines are shown without their prefix.
+func wordDiff(diff string) string {
+	var out strings.Builder
+	var removed, added []string
+	flush := func() {
+		if len(removed) == 0 && len(added) == 0 {
+			return
+		}
+		before := wordTokenPattern.FindAllString(strings.Join(removed, "\n"), -1)
+		after := wordTokenPattern.FindAllString(strings.Join(added, "\n"), -1)
+		writeWordOps(&out, diffWords(before, after))
+		out.WriteString("\n")
+		removed, added = nil, nil
+	}
+
+	inHunk := false
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "Index: "):
+			flush()
+			inHunk = false
+			out.WriteString(line + "\n")
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+			out.WriteString(line + "\n")
+		case !inHunk:
+			out.WriteString(line + "\n")
+		case strings.HasPrefix(line, "-"):
+			// A removal after additions starts a new block.
+			if len(added) > 0 {
+				flush()
+			}
+			removed = append(removed, line[1:])
+		case strings.HasPrefix(line, "+"):
+			added = append(added, line[1:])
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file" has no place in a word diff.
+		default:
+			flush()
+			out.WriteString(strings.TrimPrefix(line, " ") + "\n")
+		}
+	}
+	flush()
+	return out.String()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+		want   []wordOp
+	}{
+		{"unchanged", []string{"a", " ", "b"}, []string{"a", " ", "b"},
+			[]wordOp{{' ', "a"}, {' ', " "}, {' ', "b"}}},
+		{"tie puts the removal first", []string{"a"}, []string{"b"},
+			[]wordOp{{'-', "a"}, {'+', "b"}}},
+		{"changed last word", []string{"hello", " ", "world"}, []string{"hello", " ", "there"},
+			[]wordOp{{' ', "hello"}, {' ', " "}, {'-', "world"}, {'+', "there"}}},
+		{"inserted word", []string{"a", " ", "c"}, []string{"a", " ", "b", " ", "c"},
+			[]wordOp{{' ', "a"}, {' ', " "}, {'+', "b"}, {'+', " "}, {' ', "c"}}},
+		{"only removed", []string{"a"}, nil, []wordOp{{'-', "a"}}},
+		{"only added", nil, []string{"a"}, []wordOp{{'+', "a"}}},
+		{"nothing", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffWords(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffWords(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffWordsFallback(t *testing.T) {
+	// Past maxWordDiffCells, even identical blocks are shown as wholly
+	// removed and added rather than compared.
+	tokens := strings.Split(strings.Repeat("x", 2001), "")
+	ops := diffWords(tokens, tokens)
+	if len(ops) != 2*len(tokens) {
+		t.Fatalf("got %d ops, want %d", len(ops), 2*len(tokens))
+	}
+	for i, op := range ops {
+		want := byte('-')
+		if i >= len(tokens) {
+			want = '+'
+		}
+		if op.Kind != want {
+			t.Fatalf("ops[%d].Kind = %q, want %q", i, op.Kind, want)
+		}
+	}
+}
+
+func TestWriteWordOps(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []wordOp
+		want string
+	}{
+		{"kept text", []wordOp{{' ', "a"}, {' ', " "}, {' ', "b"}}, "a b"},
+		{"runs are merged", []wordOp{{'-', "a"}, {'-', " "}, {'-', "b"}, {'+', "c"}}, "[-a b-]{+c+}"},
+		{"markers stop at line breaks", []wordOp{{'-', "a"}, {'-', "\n"}, {'-', "b"}}, "[-a-]\n[-b-]"},
+		{"empty segments get no markers", []wordOp{{' ', "a"}, {'+', "\n"}, {' ', "b"}}, "a\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			writeWordOps(&out, tt.ops)
+			if got := out.String(); got != tt.want {
+				t.Errorf("writeWordOps() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	header := "Index: f.txt\n==================================================================\n--- f.txt\n+++ f.txt\n"
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"changed word between context lines",
+			header + "@@ -1,3 +1,3 @@\n ctx\n-hello world\n+hello there\n ctx2\n",
+			header + "@@ -1,3 +1,3 @@\nctx\nhello [-world-]{+there+}\nctx2\n"},
+		{"multi-line block",
+			header + "@@ -1,2 +1,2 @@\n-a b\n-c d\n+a x\n+c d\n",
+			header + "@@ -1,2 +1,2 @@\na [-b-]{+x+}\nc d\n"},
+		{"removal after additions starts a new block",
+			header + "@@ -1,2 +1,2 @@\n-one\n+two\n-three\n+four\n",
+			header + "@@ -1,2 +1,2 @@\n[-one-]{+two+}\n[-three-]{+four+}\n"},
+		{"no newline marker is dropped",
+			header + "@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n",
+			header + "@@ -1 +1 @@\n[-a-]{+b+}\n"},
+		{"added lines only",
+			header + "@@ -0,0 +1,2 @@\n+x\n+y\n",
+			header + "@@ -0,0 +1,2 @@\n{+x+}\n{+y+}\n"},
+		{"header lines of the next file are kept",
+			header + "@@ -1 +1 @@\n-a\n+b\nIndex: g.txt\n--- g.txt\n+++ g.txt\n@@ -1 +1 @@\n-c\n+d\n",
+			header + "@@ -1 +1 @@\n[-a-]{+b+}\nIndex: g.txt\n--- g.txt\n+++ g.txt\n@@ -1 +1 @@\n[-c-]{+d+}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wordDiff(tt.diff); got != tt.want {
+				t.Errorf("wordDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}